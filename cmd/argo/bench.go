@@ -0,0 +1,30 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Tecu23/argov2/pkg/engine"
+	"github.com/Tecu23/argov2/pkg/uci"
+)
+
+// runBench runs the bench subcommand, "argo bench [depth]", and returns the
+// exit code. It searches with the default options, so a single thread keeps
+// the node count reproducible.
+func runBench(eng *engine.Engine, args []string) int {
+	depth := uci.DefaultBenchDepth
+	if len(args) > 0 {
+		var err error
+		if depth, err = strconv.Atoi(args[0]); err != nil || depth < 1 {
+			fmt.Fprintf(os.Stderr, "invalid bench depth %q\n", args[0])
+			return 1
+		}
+	}
+
+	uci.Bench(eng, depth, os.Stdout)
+	return 0
+}
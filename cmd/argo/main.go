@@ -5,6 +5,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"log"
 	"os"
@@ -37,27 +38,62 @@ func main() {
 	flag.Parse()
 	initHelpers()
 
-	logger := log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile)
+	// Subcommands run instead of the UCI loop. Perft only needs the move
+	// generator and net only reads network files, the others evaluate
+	// positions.
+	switch flag.Arg(0) {
+	case "perft":
+		os.Exit(runPerft(flag.Args()[1:]))
+	case "net":
+		os.Exit(runNet(flag.Args()[1:]))
+	}
 
-	options := engine.NewOptions()
-	engine := engine.NewEngine(options)
+	// Without build tag 'embed' the network is loaded through EvalFile
+	if err := nnue.InitializeNNUE(); err != nil && !errors.Is(err, nnue.ErrNoNetwork) {
+		log.Fatalf("Error initializing NNUE: %v", err)
+	}
+
+	eng := engine.NewEngine(engine.NewOptions())
+
+	switch flag.Arg(0) {
+	case "bench":
+		os.Exit(runBench(eng, flag.Args()[1:]))
+	case "suite":
+		os.Exit(runSuite(eng, flag.Args()[1:]))
+	}
 
-	protocol := uci.New(name, author, version, engine, []uci.Option{})
+	logger := log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile)
+	protocol := uci.New(name, author, version, eng, uciOptions(eng))
 	protocol.Run(logger)
 }
 
+// uciOptions returns the UCI options that change the engine settings
+func uciOptions(eng *engine.Engine) []uci.Option {
+	opts := &eng.Options
+	return []uci.Option{
+		&uci.SpinOption{Name: "Hash", Min: engine.MinHash, Max: engine.MaxHash, Value: &opts.Hash},
+		&uci.ButtonOption{Name: "Clear Hash", Action: eng.Clear},
+		&uci.SpinOption{Name: "Threads", Min: 1, Max: engine.MaxThreads, Value: &opts.Threads},
+		&uci.SpinOption{Name: "MultiPV", Min: 1, Max: engine.MaxMultiPV, Value: &opts.MultiPV},
+		&uci.SpinOption{Name: "Move Overhead", Min: 0, Max: engine.MaxMoveOverhead, Value: &opts.MoveOverhead},
+		&uci.StringOption{Name: "EvalFile", Value: &opts.EvalFile},
+		&uci.BoolOption{Name: "OwnBook", Value: &opts.OwnBook},
+		&uci.StringOption{Name: "BookFile", Value: &opts.BookFile},
+		&uci.SpinOption{Name: "BookDepth", Min: 1, Max: engine.MaxBookDepth, Value: &opts.BookDepth},
+		&uci.StringOption{Name: "SyzygyPath", Value: &opts.SyzygyPath},
+		&uci.SpinOption{Name: "SyzygyProbeLimit", Min: 0, Max: engine.MaxSyzygyProbeLimit, Value: &opts.SyzygyProbeLimit},
+	}
+}
+
 func initHelpers() {
 	attacks.InitPawnAttacks()
 	attacks.InitKnightAttacks()
 	attacks.InitKingAttacks()
+	attacks.InitRays()
 	attacks.InitSliderPiecesAttacks(constants.Bishop)
 	attacks.InitSliderPiecesAttacks(constants.Rook)
 
 	util.InitFen2Sq()
 
 	hash.Init()
-
-	if err := nnue.InitializeNNUE(); err != nil {
-		log.Fatalf("Error initializing NNUE: %v", err)
-	}
 }
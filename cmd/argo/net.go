@@ -0,0 +1,151 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Tecu23/argov2/pkg/nnue"
+)
+
+// runNet runs the net subcommand and returns the exit code:
+//
+//	argo net info FILE...
+//	argo net convert [-desc TEXT] LEGACY OUT
+//
+// Info prints the header of network files and whether this build can load
+// them. Convert writes a network of the legacy format, which is only the
+// weights, in the container format with a header and hash.
+func runNet(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "info":
+			return netInfo(args[1:])
+		case "convert":
+			return netConvert(args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, "usage: argo net info FILE... | argo net convert [-desc TEXT] LEGACY OUT")
+	return 1
+}
+
+func netInfo(paths []string) int {
+	code := 0
+	for _, path := range paths {
+		if err := printNetInfo(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+func printNetInfo(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h, err := nnue.ReadHeader(bufio.NewReader(f))
+	if errors.Is(err, nnue.ErrLegacyFormat) {
+		fmt.Printf("%s: legacy format, no header\n", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: version %d\n", path, h.Version)
+	if h.Description != "" {
+		fmt.Printf("  description: %s\n", h.Description)
+	}
+	fmt.Printf("  layers: %d -> 2x%d -> %d\n", h.InputSize, h.HiddenSize, h.OutputSize)
+	// Version 1 headers end with the dimensions
+	if h.Version >= 2 {
+		fmt.Printf("  king buckets: %d\n", h.KingBuckets)
+		fmt.Printf("  multipliers: input %d, hidden %d\n", h.InputMultiplier, h.HiddenMultiplier)
+	}
+	if err := h.Verify(); err != nil {
+		fmt.Printf("  not loadable: %v\n", err)
+	} else {
+		fmt.Println("  loadable by this build")
+	}
+	return nil
+}
+
+func netConvert(args []string) int {
+	fs := flag.NewFlagSet("net convert", flag.ExitOnError)
+	description := fs.String("desc", "", "description stored in the header")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: argo net convert [-desc TEXT] LEGACY OUT")
+		return 1
+	}
+	if err := convertNet(fs.Arg(0), fs.Arg(1), *description); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// convertNet converts the network in in and writes it to out. The network is
+// converted in memory and written to a temporary file next to out, which
+// replaces out only once it is complete, so a failed conversion leaves out as
+// it was.
+func convertNet(in, out, description string) error {
+	if sameFile(in, out) {
+		return fmt.Errorf("%s: can't convert a network onto itself", in)
+	}
+
+	src, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var buf bytes.Buffer
+	if err := nnue.ConvertLegacy(bufio.NewReader(src), &buf, description); err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(out), ".argo-net-*")
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(tmp)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), out)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// sameFile reports whether the paths name the same file, also through links
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA == nil && errB == nil && absA == absB {
+		return true
+	}
+
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/epd"
+)
+
+// runPerft runs the perft subcommand and returns the exit code:
+//
+//	argo perft [-depth N] [-fen FEN] [-threads N] [-hash MB]
+//	argo perft -suite FILE [-depth N] [-threads N] [-hash MB]
+//
+// For a single position the node count below every root move is printed,
+// followed by the total. A suite file holds one position per line with the
+// expected node counts as "D<depth> <nodes>" operations. Every count up to the
+// given depth is checked and the mismatches are reported.
+func runPerft(args []string) int {
+	fs := flag.NewFlagSet("perft", flag.ExitOnError)
+	depth := fs.Int("depth", 5, "perft depth, for suites the deepest count checked")
+	fen := fs.String("fen", constants.StartPosition, "position to count")
+	threads := fs.Int("threads", runtime.NumCPU(), "goroutines sharing the root moves")
+	hashSize := fs.Int("hash", 0, "hash table size in MB, 0 disables it")
+	suite := fs.String("suite", "", "EPD file with expected node counts")
+	fs.Parse(args)
+
+	var table *board.PerftTable
+	if *hashSize > 0 {
+		table = board.NewPerftTable(*hashSize)
+	}
+
+	if *suite != "" {
+		return perftSuite(*suite, *depth, *threads, table)
+	}
+
+	b, err := board.ParseFEN(*fen)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	start := time.Now()
+	results := board.Divide(&b, *depth, *threads, table)
+	elapsed := time.Since(start)
+
+	// Sorted output can be diffed against the divide of other engines
+	slices.SortFunc(results, func(x, y board.PerftResult) int {
+		return strings.Compare(x.Move.String(), y.Move.String())
+	})
+
+	nodes := int64(0)
+	for _, result := range results {
+		fmt.Printf("%v: %d\n", result.Move, result.Nodes)
+		nodes += result.Nodes
+	}
+	fmt.Printf("\nNodes: %d Time: %d ms NPS: %.0f\n", nodes, elapsed.Milliseconds(), float64(nodes)/elapsed.Seconds())
+	return 0
+}
+
+// perftSuite checks the node counts of every position in an EPD perft suite.
+func perftSuite(path string, maxDepth, threads int, table *board.PerftTable) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	positions, err := epd.ReadAll(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+
+	start := time.Now()
+	checks, mismatches := 0, 0
+	for _, p := range positions {
+		for _, op := range p.Operations {
+			depth, err := strconv.Atoi(strings.TrimPrefix(op.Opcode, "D"))
+			if !strings.HasPrefix(op.Opcode, "D") || err != nil || depth > maxDepth || len(op.Operands) == 0 {
+				continue
+			}
+			expected, err := strconv.ParseInt(op.Operands[0], 10, 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s:%d: invalid node count %q\n", path, p.Line, op.Operands[0])
+				return 1
+			}
+
+			checks++
+			if got := board.Perft(&p.Board, depth, threads, table); got != expected {
+				mismatches++
+				fmt.Printf("MISMATCH line %d depth %d: got %d, want %d\n  %s\n", p.Line, depth, got, expected, p.Board.FEN())
+			}
+		}
+	}
+
+	fmt.Printf("\nPositions: %d Checks: %d Mismatches: %d Time: %d ms\n",
+		len(positions), checks, mismatches, time.Since(start).Milliseconds())
+	if mismatches > 0 {
+		return 1
+	}
+	return 0
+}
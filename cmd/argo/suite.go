@@ -0,0 +1,110 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/engine"
+	"github.com/Tecu23/argov2/pkg/epd"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// runSuite runs the suite subcommand and returns the exit code:
+//
+//	argo suite [-movetime MS | -nodes N | -depth N] [-threads N] [-hash MB] FILE...
+//
+// Every position of the EPD files is searched with the given limit, by default
+// one second, and checked against its bm, am and dm operations.
+func runSuite(eng *engine.Engine, args []string) int {
+	fs := flag.NewFlagSet("suite", flag.ExitOnError)
+	moveTime := fs.Int("movetime", 0, "search time per position in ms")
+	nodes := fs.Int("nodes", 0, "nodes per position")
+	depth := fs.Int("depth", 0, "depth per position")
+	fs.IntVar(&eng.Options.Threads, "threads", eng.Options.Threads, "search threads")
+	fs.IntVar(&eng.Options.Hash, "hash", eng.Options.Hash, "hash table size in MB")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: argo suite [flags] FILE...")
+		return 1
+	}
+
+	limits := LimitsType{MoveTime: *moveTime, Nodes: *nodes, Depth: *depth}
+	if limits == (LimitsType{}) {
+		limits.MoveTime = 1000
+	}
+
+	var tests []*epd.Test
+	for _, path := range fs.Args() {
+		fileTests, err := readTests(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			return 1
+		}
+		tests = append(tests, fileTests...)
+	}
+
+	summary := epd.Run(context.Background(), eng, tests, limits, func(r epd.Result) {
+		status := "failed"
+		if r.Solved {
+			status = fmt.Sprintf("solved in %.2fs", r.SolveTime.Seconds())
+		}
+		bestMove := "(none)"
+		if mv := r.BestMove(); mv != move.NoMove {
+			bestMove = r.Test.Board.SAN(mv)
+		}
+		fmt.Printf("%-24s %-8s %-16s %s\n", r.Test.ID(), bestMove, expectation(r.Test), status)
+	})
+
+	solveTime := 0.0
+	if summary.Solved > 0 {
+		solveTime = summary.SolveTime.Seconds() / float64(summary.Solved)
+	}
+	fmt.Printf("\nSolved: %d/%d Failed: %d Score: %d/%d (%.1f%%)\n",
+		summary.Solved, summary.Tests, summary.Failed(),
+		summary.Points, summary.MaxPoints, 100*float64(summary.Points)/float64(max(summary.MaxPoints, 1)))
+	fmt.Printf("Average solve time: %.2fs Nodes: %d Time: %.1fs\n",
+		solveTime, summary.Nodes, summary.Time.Seconds())
+	return 0
+}
+
+func readTests(path string) ([]*epd.Test, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	positions, err := epd.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	tests := make([]*epd.Test, 0, len(positions))
+	for _, p := range positions {
+		t, err := epd.NewTest(p)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, t)
+	}
+	return tests, nil
+}
+
+// expectation describes what a test expects, as written in the EPD file.
+func expectation(t *epd.Test) string {
+	var parts []string
+	for _, opcode := range []string{"bm", "am", "dm"} {
+		if operands, ok := t.Operation(opcode); ok {
+			parts = append(parts, opcode+" "+strings.Join(operands, " "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -4,7 +4,10 @@
 // Package history
 package history
 
-import "github.com/Tecu23/argov2/pkg/color"
+import (
+	"github.com/Tecu23/argov2/pkg/color"
+	"github.com/Tecu23/argov2/pkg/move"
+)
 
 const (
 	historyMax = 10000
@@ -42,6 +45,11 @@ func (h *HistoryTable) Get(color color.Color, from, to int) int {
 	return h.scores[color][from][to]
 }
 
+// Score returns the history score of a move for the side making it.
+func (h *HistoryTable) Score(m move.Move) int {
+	return h.scores[m.GetMovingPieceColor()][m.GetSourceSquare()][m.GetTargetSquare()]
+}
+
 func (h *HistoryTable) GetButterfly(from, to int) int {
 	return h.scores[0][from][to] + h.scores[1][from][to]
 }
@@ -21,7 +21,10 @@ type SearchInfo struct {
 	Depth    int
 	Nodes    int64
 	Time     time.Duration
+	HashFull int   // Permille of the transposition table in use
+	TBHits   int64 // Number of positions found in the endgame tablebases
 	MainLine []move.Move
+	MultiPV  int // Rank of the line in MultiPV mode, 0 when a single line is searched
 }
 
 type LimitsType struct {
@@ -39,7 +42,8 @@ type LimitsType struct {
 }
 
 type SearchParams struct {
-	Boards   []board.Board
-	Limits   LimitsType
-	Progress func(si SearchInfo)
+	Boards    []board.Board
+	Limits    LimitsType
+	Progress  func(si SearchInfo)
+	PonderHit <-chan struct{} // Closed when the GUI sends ponderhit during a ponder search
 }
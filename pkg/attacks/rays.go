@@ -0,0 +1,60 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+// Package attacks contains the pre-computed attack tables for all pieces.
+// For sliding pieces (bishop, rook, queens) it uses magic numbers for indexing
+package attacks
+
+import (
+	"github.com/Tecu23/argov2/pkg/bitboard"
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+// Between[a][b] holds the squares strictly between a and b when they share a
+// rank, file or diagonal, and is empty otherwise.
+var Between [64][64]bitboard.Bitboard
+
+// Line[a][b] holds the whole rank, file or diagonal through a and b, from edge
+// to edge, and is empty when the squares aren't aligned.
+var Line [64][64]bitboard.Bitboard
+
+// rayDirections are the rank and file steps of the eight sliding directions.
+var rayDirections = [8][2]int{
+	{-1, 0}, {1, 0}, {0, -1}, {0, 1},
+	{-1, -1}, {-1, 1}, {1, -1}, {1, 1},
+}
+
+// InitRays initializes the Between and Line tables. They are used to find
+// pinned pieces and the squares that block a check.
+func InitRays() {
+	for sq := A8; sq <= H1; sq++ {
+		for _, dir := range rayDirections {
+			// The full line through sq in this direction and the opposite one
+			line := ray(sq, dir[0], dir[1]) | ray(sq, -dir[0], -dir[1])
+			line.Set(sq)
+
+			between := bitboard.Bitboard(0)
+			r, f := sq/8+dir[0], sq%8+dir[1]
+			for ; onBoard(r, f); r, f = r+dir[0], f+dir[1] {
+				target := r*8 + f
+				Between[sq][target] = between
+				Line[sq][target] = line
+				between.Set(target)
+			}
+		}
+	}
+}
+
+// ray returns the squares from sq (exclusive) to the edge of the board in the
+// direction given by the rank and file steps.
+func ray(sq, dr, df int) bitboard.Bitboard {
+	result := bitboard.Bitboard(0)
+	for r, f := sq/8+dr, sq%8+df; onBoard(r, f); r, f = r+dr, f+df {
+		result.Set(r*8 + f)
+	}
+	return result
+}
+
+func onBoard(rank, file int) bool {
+	return rank >= 0 && rank < 8 && file >= 0 && file < 8
+}
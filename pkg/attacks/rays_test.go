@@ -0,0 +1,72 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package attacks
+
+import (
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/bitboard"
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+func squares(sqs ...int) bitboard.Bitboard {
+	var bb bitboard.Bitboard
+	for _, sq := range sqs {
+		bb.Set(sq)
+	}
+	return bb
+}
+
+func TestBetween(t *testing.T) {
+	InitRays()
+
+	testCases := []struct {
+		name     string
+		from, to int
+		expected bitboard.Bitboard
+	}{
+		{"Same Rank", A1, E1, squares(B1, C1, D1)},
+		{"Same File Reversed", E8, E4, squares(E7, E6, E5)},
+		{"Diagonal", A1, D4, squares(B2, C3)},
+		{"Anti-Diagonal", H1, E4, squares(G2, F3)},
+		{"Adjacent", D4, E5, 0},
+		{"Not Aligned", A1, B3, 0},
+		{"Same Square", D4, D4, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Between[tc.from][tc.to]; got != tc.expected {
+				t.Errorf("Between = %v, want %v", got, tc.expected)
+			}
+			if got := Between[tc.to][tc.from]; got != tc.expected {
+				t.Errorf("Between reversed = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLine(t *testing.T) {
+	InitRays()
+
+	testCases := []struct {
+		name     string
+		from, to int
+		expected bitboard.Bitboard
+	}{
+		{"Rank", C1, E1, squares(A1, B1, C1, D1, E1, F1, G1, H1)},
+		{"File", A8, A2, squares(A1, A2, A3, A4, A5, A6, A7, A8)},
+		{"Diagonal", C3, E5, squares(A1, B2, C3, D4, E5, F6, G7, H8)},
+		{"Short Diagonal", B1, A2, squares(B1, A2)},
+		{"Not Aligned", A1, B3, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Line[tc.from][tc.to]; got != tc.expected {
+				t.Errorf("Line = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
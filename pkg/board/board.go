@@ -30,8 +30,20 @@ type Board struct {
 	Bitboards   [12]bitboard.Bitboard
 	Occupancies [3]bitboard.Bitboard
 
+	// pieces is a mailbox holding the piece on every occupied square, kept in
+	// sync with the bitboards. Empty squares are only known from Occupancies.
+	pieces [64]int
+
 	Castlings Castlings
 
+	// Chess960 enables Fischer Random castling: castling moves are encoded as
+	// the king capturing its own rook instead of moving two squares.
+	Chess960 bool
+
+	// castlingRooks holds the start square of the rook for each castling right,
+	// indexed by the right's bit position (ShortW, LongW, ShortB, LongB).
+	castlingRooks [4]int
+
 	EnPassant int
 
 	SideToMove color.Color
@@ -41,7 +53,29 @@ type Board struct {
 
 	hash uint64
 
-	// should keep some history to fix threefold repetition
+	// history holds the hashes of all positions that led to the current one,
+	// oldest first. It is used to detect repetitions.
+	history []uint64
+
+	// nullPly is the length of history right after the last null move. Positions
+	// before a null move are not real predecessors, so repetition detection
+	// never looks past it.
+	nullPly int
+
+	// undos holds one record for every move made with MakeMove or
+	// MakeNullMove that hasn't been taken back yet.
+	undos []undo
+}
+
+// undo holds the state MakeMove can't recompute when the move is taken back.
+type undo struct {
+	move          move.Move
+	captured      int
+	castlings     Castlings
+	enPassant     int
+	halfMoveClock uint8
+	hash          uint64
+	nullPly       int
 }
 
 // Reset restores the board to an initial empty state and sets defaults.
@@ -51,9 +85,11 @@ func (b *Board) Reset() {
 	b.EnPassant = -1
 
 	b.Castlings = 0
+	b.Chess960 = false
+	b.castlingRooks = defaultCastlingRooks
 
 	b.HalfMoveClock = 0
-	b.FullMoveCounter = 0
+	b.FullMoveCounter = 1
 
 	for i := 0; i < 12; i++ {
 		b.Bitboards[i] = 0
@@ -63,7 +99,15 @@ func (b *Board) Reset() {
 		b.Occupancies[i] = 0
 	}
 
-	b.calculateHash()
+	for sq := range b.pieces {
+		b.pieces[sq] = Empty
+	}
+
+	b.history = nil
+	b.nullPly = 0
+	b.undos = nil
+
+	b.hash = b.calculateHash()
 }
 
 func NewBoard() *Board {
@@ -73,9 +117,13 @@ func NewBoard() *Board {
 	return b
 }
 
-// CopyBoard creates a copy of the board's current state.
+// CopyBoard creates a copy of the board's current state. The copy gets its own
+// undo stack and history on the first move it makes, so moves made on either
+// board never overwrite the undo records or position hashes of the other.
 func (b Board) CopyBoard() Board {
 	boardCopy := b
+	boardCopy.undos = b.undos[:len(b.undos):len(b.undos)]
+	boardCopy.history = b.history[:len(b.history):len(b.history)]
 	return boardCopy
 }
 
@@ -87,36 +135,34 @@ func (b *Board) TakeBack(cpy Board) {
 
 // SetSq should set a square sq to a particular piece pc
 func (b *Board) SetSq(piece, sq int) {
-	pieceColor := util.PcColor(piece)
-
 	if b.Occupancies[color.BOTH].Test(sq) {
-		// need to remove the piece
-		for p := WP; p <= BK; p++ {
-			if b.Bitboards[p].Test(sq) {
-				b.Bitboards[p].Clear(sq)
-				b.hash ^= hash.HashTable.PieceSquare[p*64+sq]
-			}
-		}
-
-		b.Occupancies[color.BOTH].Clear(sq)
-		b.Occupancies[color.WHITE].Clear(sq)
-		b.Occupancies[color.BLACK].Clear(sq)
+		b.removePiece(sq)
 	}
 
 	if piece == Empty {
 		return
 	}
-	b.hash ^= hash.HashTable.PieceSquare[piece*64+sq]
-	b.Bitboards[piece].Set(sq)
+	b.putPiece(piece, sq)
+}
 
-	if pieceColor == color.WHITE {
-		b.Occupancies[color.WHITE].Set(sq)
-	} else {
-		b.Occupancies[color.BLACK].Set(sq)
-	}
+// putPiece places a piece on an empty square, updating the bitboards, the
+// mailbox and the hash.
+func (b *Board) putPiece(piece, sq int) {
+	b.Bitboards[piece].Set(sq)
+	b.Occupancies[util.PcColor(piece)].Set(sq)
+	b.Occupancies[color.BOTH].Set(sq)
+	b.pieces[sq] = piece
+	b.hash ^= hash.HashTable.PieceSquare[piece*64+sq]
+}
 
-	b.Occupancies[color.BOTH] |= b.Occupancies[color.WHITE]
-	b.Occupancies[color.BOTH] |= b.Occupancies[color.BLACK]
+// removePiece lifts the piece standing on an occupied square.
+func (b *Board) removePiece(sq int) {
+	piece := b.pieces[sq]
+	b.Bitboards[piece].Clear(sq)
+	b.Occupancies[util.PcColor(piece)].Clear(sq)
+	b.Occupancies[color.BOTH].Clear(sq)
+	b.pieces[sq] = Empty
+	b.hash ^= hash.HashTable.PieceSquare[piece*64+sq]
 }
 
 // IsSquareAttacked checks if a given square is attacked by the specified side (WHITE or BLACK).
@@ -184,192 +230,192 @@ func (b *Board) IsSquareAttacked(sq int, side color.Color) bool {
 	return false
 }
 
-// TODO: REDO THIS for better performance
-
-// MakeMove attempts to make a move on the board. It updates board state (bitboards,
-// occupancy, castling, en passant) and returns true if successful. If the move leaves
-// own king in check, it reverts and returns false. moveFlag determines whether to consider
-// only captures or all moves.
+// MakeMove makes a move on the board, updating the pieces, castling rights, en
+// passant square, clocks and hash in place. An undo record is pushed so the move
+// can be taken back with UnmakeMove. If the move leaves the own king in check it
+// is taken back right away and false is returned. With moveFlag OnlyCaptures
+// quiet moves are rejected without being made.
 func (b *Board) MakeMove(m move.Move, moveFlag int) bool {
-	// If moveFlag == OnlyCaptures, we only proceed if this move is a capture move.
-	// Otherwise, we handle all moves.
-	if moveFlag == AllMoves {
-		// preserve board state
-		copyB := b.CopyBoard()
-
-		src := m.GetSourceSquare()
-		tgt := m.GetTargetSquare()
-
-		pc := m.GetMovingPiece()
-		clr := util.PcColor(pc)
-
-		prom := m.GetPromotionPiece()
-
-		dblPwn := m.IsDoublePawnPush()
+	if moveFlag == OnlyCaptures && !m.IsCapture() {
+		return false
+	}
 
-		ep := m.IsEnPassant()
+	src := m.GetSourceSquare()
+	tgt := m.GetTargetSquare()
 
-		cast := m.IsCastle()
+	pc := m.GetMovingPiece()
+	clr := util.PcColor(pc)
 
-		// If there was an en passant square, remove it from hash
-		if b.EnPassant != -1 {
-			file := b.EnPassant % 8
-			b.hash ^= hash.HashTable.EnPassant[file]
-		}
-		b.EnPassant = -1
-
-		// Handle en passant
-		if ep {
-			b.SetSq(Empty, src)
-			if clr == color.WHITE {
-				b.SetSq(Empty, tgt+8)
-			} else {
-				b.SetSq(Empty, tgt-8)
-			}
+	// The piece on the target square is read from the board rather than from
+	// the move, so moves stored in the transposition table stay usable
+	captured, capSq := Empty, tgt
+	if m.IsEnPassant() {
+		capSq = tgt ^ 8
+	}
+	if !m.IsCastle() && b.Occupancies[color.BOTH].Test(capSq) {
+		captured = b.pieces[capSq]
+	}
 
-			b.SetSq(pc, tgt)
+	b.undos = append(b.undos, undo{
+		move:          m,
+		captured:      captured,
+		castlings:     b.Castlings,
+		enPassant:     b.EnPassant,
+		halfMoveClock: b.HalfMoveClock,
+		hash:          b.hash,
+		nullPly:       b.nullPly,
+	})
 
-			b.hash ^= hash.HashTable.Side
-			b.SideToMove = b.SideToMove.Opp()
+	// Remember the position we are leaving so repetitions can be detected
+	b.history = append(b.history, b.hash)
 
-			// Check for checks to ensure move legality
-			var kingPos int
-			if b.SideToMove == color.WHITE {
-				if b.Bitboards[BK] == 0 {
-					b.TakeBack(copyB)
-					return false
-				}
-				kingPos = b.Bitboards[BK].FirstOne()
-			} else {
-				if b.Bitboards[WK] == 0 {
-					b.TakeBack(copyB)
-					return false
-				}
-				kingPos = b.Bitboards[WK].FirstOne()
-			}
-			if b.IsSquareAttacked(kingPos, b.SideToMove) {
-				// take back
-				b.TakeBack(copyB)
-				return false
-			}
-			if b.SideToMove == color.WHITE {
-				b.Bitboards[BK].Set(kingPos)
-			} else {
-				b.Bitboards[WK].Set(kingPos)
-			}
+	// Pawn moves and captures reset the fifty-move counter
+	if pc == WP || pc == BP || captured != Empty {
+		b.HalfMoveClock = 0
+	} else {
+		b.HalfMoveClock++
+	}
 
-			if b.SideToMove == color.BLACK {
-				b.FullMoveCounter++
-			}
-			return true
-		}
+	// If there was an en passant square, remove it from hash
+	if b.EnPassant != -1 {
+		b.hash ^= hash.HashTable.EnPassant[b.EnPassant%8]
+	}
+	b.EnPassant = -1
 
-		// Handle castling
-		if cast {
-			switch tgt {
-			// WHITE Short Castle
-			case G1:
-				b.SetSq(Empty, H1)
-				b.SetSq(WR, F1)
-			// WHITE Long Castle
-			case C1:
-				b.SetSq(Empty, A1)
-				b.SetSq(WR, D1)
-			// BLACK Short Castle
-			case G8:
-				b.SetSq(Empty, H8)
-				b.SetSq(BR, F8)
-			// BLACK Long Castle
-			case C8:
-				b.SetSq(Empty, A8)
-				b.SetSq(BR, D8)
-			}
-		}
+	// Handle castling. King and rook are both lifted first since in
+	// Chess960 either may land on the other's start square.
+	if m.IsCastle() {
+		kingFrom, kingTo, rookFrom, rookTo := b.CastlingSquares(m)
+		rook := b.pieces[rookFrom]
 
-		// Double push pawn update
-		if dblPwn {
-			if clr == color.WHITE {
-				b.EnPassant = src - 8
-				b.hash ^= hash.HashTable.EnPassant[(src-8)%8]
-			} else {
-				b.EnPassant = src + 8
-				b.hash ^= hash.HashTable.EnPassant[(src+8)%8]
-			}
+		b.removePiece(kingFrom)
+		b.removePiece(rookFrom)
+		b.putPiece(rook, rookTo)
+		b.putPiece(pc, kingTo)
+	} else {
+		if captured != Empty {
+			b.removePiece(capSq)
 		}
-
-		b.SetSq(Empty, src)
+		b.removePiece(src)
 
 		if m.IsPromotion() {
-			b.SetSq(prom, tgt)
+			b.putPiece(m.GetPromotionPiece(), tgt)
 		} else {
-			b.SetSq(pc, tgt)
+			b.putPiece(pc, tgt)
 		}
+	}
 
-		// Update castling rights if necessary
-		oldCastling := b.Castlings
-		b.Castlings &= Castlings(CastlingRights[src])
-		b.Castlings &= Castlings(CastlingRights[tgt])
-
-		// Update hash for changed castling rights
-		if oldCastling != b.Castlings {
-			if uint(oldCastling)&ShortW != uint(b.Castlings)&ShortW {
-				b.hash ^= hash.HashTable.Castling[0]
-			}
-			if uint(oldCastling)&LongW != uint(b.Castlings)&LongW {
-				b.hash ^= hash.HashTable.Castling[1]
-			}
-			if uint(oldCastling)&ShortB != uint(b.Castlings)&ShortB {
-				b.hash ^= hash.HashTable.Castling[2]
-			}
-			if uint(oldCastling)&LongB != uint(b.Castlings)&LongB {
-				b.hash ^= hash.HashTable.Castling[3]
-			}
-		}
+	// Double push pawn update
+	if m.IsDoublePawnPush() {
+		b.EnPassant = (src + tgt) / 2
+		b.hash ^= hash.HashTable.EnPassant[b.EnPassant%8]
+	}
 
-		// change side
-		b.hash ^= hash.HashTable.Side
-		b.SideToMove = b.SideToMove.Opp()
+	// Update castling rights if necessary
+	oldCastling := b.Castlings
+	b.Castlings = b.castlingsAfter(pc, src, tgt)
 
-		// Check if own king is in check after the move
-		var kingPos int
-		if b.SideToMove == color.WHITE {
-			if b.Bitboards[BK] == 0 {
-				b.TakeBack(copyB)
-				return false
-			}
-			kingPos = b.Bitboards[BK].FirstOne()
-		} else {
-			if b.Bitboards[WK] == 0 {
-				b.TakeBack(copyB)
-				return false
-			}
-			kingPos = b.Bitboards[WK].FirstOne()
+	// Update hash for changed castling rights
+	if oldCastling != b.Castlings {
+		if uint(oldCastling)&ShortW != uint(b.Castlings)&ShortW {
+			b.hash ^= hash.HashTable.Castling[0]
 		}
-		if b.IsSquareAttacked(kingPos, b.SideToMove) {
-			// take back
-			b.TakeBack(copyB)
-			return false
+		if uint(oldCastling)&LongW != uint(b.Castlings)&LongW {
+			b.hash ^= hash.HashTable.Castling[1]
 		}
-		if b.SideToMove == color.WHITE {
-			b.Bitboards[BK].Set(kingPos)
-		} else {
-			b.Bitboards[WK].Set(kingPos)
+		if uint(oldCastling)&ShortB != uint(b.Castlings)&ShortB {
+			b.hash ^= hash.HashTable.Castling[2]
 		}
-	} else { // capture moves
-		if m.IsCapture() {
-			return b.MakeMove(m, AllMoves)
+		if uint(oldCastling)&LongB != uint(b.Castlings)&LongB {
+			b.hash ^= hash.HashTable.Castling[3]
 		}
-		return false // 0 means don't make it
 	}
 
-	if b.SideToMove == color.BLACK {
+	// change side
+	b.hash ^= hash.HashTable.Side
+	b.SideToMove = b.SideToMove.Opp()
+
+	// The fullmove counter is incremented after Black's move
+	if b.SideToMove == color.WHITE {
 		b.FullMoveCounter++
 	}
+
+	// Check if own king is in check after the move
+	kingPos := b.kingSquare(clr)
+	if kingPos == 64 || b.IsSquareAttacked(kingPos, b.SideToMove) {
+		b.UnmakeMove()
+		return false
+	}
 	return true
 }
 
-// MakeNullMove switches the side to move without making any actual move
+// UnmakeMove takes back the last move made with MakeMove or MakeNullMove,
+// restoring the position exactly as it was before the move.
+func (b *Board) UnmakeMove() {
+	u := b.undos[len(b.undos)-1]
+	b.undos = b.undos[:len(b.undos)-1]
+	b.history = b.history[:len(b.history)-1]
+
+	b.SideToMove = b.SideToMove.Opp()
+
+	m := u.move
+	if m != move.NoMove && b.SideToMove == color.BLACK {
+		b.FullMoveCounter--
+	}
+
+	switch {
+	case m == move.NoMove:
+		// A null move only changed the side to move and the en passant square
+
+	case m.IsCastle():
+		kingFrom, kingTo, rookFrom, rookTo := b.CastlingSquares(m)
+		rook := b.pieces[rookTo]
+
+		b.removePiece(kingTo)
+		b.removePiece(rookTo)
+		b.putPiece(rook, rookFrom)
+		b.putPiece(m.GetMovingPiece(), kingFrom)
+
+	default:
+		src, tgt := m.GetSourceSquare(), m.GetTargetSquare()
+
+		b.removePiece(tgt)
+		b.putPiece(m.GetMovingPiece(), src)
+
+		if u.captured != Empty {
+			capSq := tgt
+			if m.IsEnPassant() {
+				capSq = tgt ^ 8
+			}
+			b.putPiece(u.captured, capSq)
+		}
+	}
+
+	b.Castlings = u.castlings
+	b.EnPassant = u.enPassant
+	b.HalfMoveClock = u.halfMoveClock
+	b.hash = u.hash
+	b.nullPly = u.nullPly
+}
+
+// MakeNullMove switches the side to move without making any actual move.
+// The null move counts as a reversible ply and starts a new repetition window.
+// It is taken back with UnmakeMove.
 func (b *Board) MakeNullMove() {
+	b.undos = append(b.undos, undo{
+		move:          move.NoMove,
+		captured:      Empty,
+		castlings:     b.Castlings,
+		enPassant:     b.EnPassant,
+		halfMoveClock: b.HalfMoveClock,
+		hash:          b.hash,
+		nullPly:       b.nullPly,
+	})
+
+	b.history = append(b.history, b.hash)
+	b.nullPly = len(b.history)
+	b.HalfMoveClock++
+
 	b.SideToMove = b.SideToMove.Opp() // Switch side (0->1 or 1->0)
 	// Update hash for side change
 	b.hash ^= hash.HashTable.Side
@@ -387,15 +433,16 @@ func (b *Board) MakeNullMove() {
 // It generates all moves, finds the one matching this string, and returns it. If not found, returns NoMove.
 func (b *Board) ParseMove(moveString string) (Board, bool) {
 	newB := b.CopyBoard()
-	moves := b.GenerateMoves()
+	var moves move.List
+	b.GenerateMoves(&moves)
 
 	src := util.Fen2Sq[moveString[:2]]
 	tgt := util.Fen2Sq[moveString[2:4]]
 
 	tmpMove := move.NoMove
 
-	for cnt := 0; cnt < len(moves); cnt++ {
-		mv := moves[cnt]
+	for cnt := 0; cnt < moves.Len(); cnt++ {
+		mv := moves.Get(cnt)
 
 		if mv.GetSourceSquare() == src && mv.GetTargetSquare() == tgt {
 			prom := mv.GetPromotionPiece()
@@ -465,7 +512,7 @@ func (b Board) PrintBoard() {
 	fmt.Printf("   Side:          %s\n", b.SideToMove.String())
 	fmt.Printf("   Enpassant:     %s\n", util.Sq2Fen[b.EnPassant])
 	fmt.Printf("   Half Moves:    %d\n", b.HalfMoveClock)
-	fmt.Printf("   Castling:   %s\n\n", b.Castlings.String())
+	fmt.Printf("   Castling:   %s\n\n", b.castlingString())
 	// fmt.Printf(" HashKey: 0x%X\n\n", b.Key)
 }
 
@@ -500,21 +547,10 @@ func (b *Board) IsCheckmate() bool {
 		return false
 	}
 
-	// Generate all possible moves
-	moves := b.GenerateMoves()
-
-	// Try each move to see if it gets us out of check
-	for _, mv := range moves {
-		copyB := b.CopyBoard()
-		if b.MakeMove(mv, AllMoves) {
-			b.TakeBack(copyB)
-			return false
-		}
-		b.TakeBack(copyB)
-	}
-
 	// No legal moves found while in check => checkmate
-	return true
+	var moves move.List
+	b.GenerateEvasions(&moves)
+	return moves.Len() == 0
 }
 
 // IsStalemate determines if the current position is stalemate
@@ -524,21 +560,10 @@ func (b *Board) IsStalemate() bool {
 		return false
 	}
 
-	// Generate all possible moves
-	moves := b.GenerateMoves()
-
-	// Try each move to see if it gets us out of check
-	for _, mv := range moves {
-		copyB := b.CopyBoard()
-		if b.MakeMove(mv, AllMoves) {
-			b.TakeBack(copyB)
-			return false // Found a legal move, not stalemate
-		}
-		b.TakeBack(copyB)
-	}
-
 	// No legal moves found while not in check => stalemate
-	return true
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	return moves.Len() == 0
 }
 
 // IsInsufficientMaterial checks if there are enough pieces left for checkmate
@@ -621,62 +646,59 @@ func (b *Board) calculateHash() uint64 {
 	return tmpHash
 }
 
-// Update hash incrementally when making moves
-func (b *Board) updateHashForMove(m move.Move) {
-	from := m.GetSourceSquare()
-	to := m.GetTargetSquare()
-	piece := m.GetMovingPiece()
-	capture := m.IsCapture()
-	promotion := m.GetPromotionPiece()
-
-	// Remove piece from source square
-	b.hash ^= hash.HashTable.PieceSquare[piece*64+from]
-
-	// Add piece to destination square
-	if m.IsPromotion() {
-		b.hash ^= hash.HashTable.PieceSquare[promotion*64+to]
-	} else {
-		b.hash ^= hash.HashTable.PieceSquare[piece*64+to]
-	}
+// SetHistory replaces the hashes of the positions that led to the current one.
+// The hashes must be ordered from the oldest position to the most recent one.
+func (b *Board) SetHistory(hashes []uint64) {
+	b.history = hashes
+	b.nullPly = 0
+}
 
-	// Handle Capture
-	if capture {
-		capturedPiece := m.GetCapturedPiece()
-		b.hash ^= hash.HashTable.PieceSquare[capturedPiece*64+to]
-	}
+// IsAfterNullMove reports whether the position was reached by a null move.
+func (b *Board) IsAfterNullMove() bool {
+	return b.nullPly > 0 && b.nullPly == len(b.history)
+}
 
-	// Update en passant
-	if b.EnPassant != -1 {
-		file := b.EnPassant % 8
-		b.hash ^= hash.HashTable.EnPassant[file]
-	}
+// IsRepetition reports whether the current position already occurred earlier
+// in the game or in the current search line. Only positions since the last
+// irreversible move (pawn move or capture) are considered.
+func (b *Board) IsRepetition() bool {
+	return b.repetitions(1)
+}
 
-	// Handle new en passant
-	if m.IsDoublePawnPush() {
-		file := to % 8
-		b.hash ^= hash.HashTable.EnPassant[file]
-	}
+// IsThreefoldRepetition reports whether the current position occurred at least
+// twice before, which makes it a draw by threefold repetition.
+func (b *Board) IsThreefoldRepetition() bool {
+	return b.repetitions(2)
+}
 
-	// Update castling rights
-	oldRights := b.Castlings
-	newRights := b.Castlings & Castlings(CastlingRights[from]) & Castlings(CastlingRights[to])
-	if oldRights != newRights {
-		if uint(oldRights)&ShortW != uint(newRights)&ShortW {
-			b.hash ^= hash.HashTable.Castling[0]
-		}
-		if uint(oldRights)&LongW != uint(newRights)&LongW {
-			b.hash ^= hash.HashTable.Castling[1]
-		}
-		if uint(oldRights)&ShortB != uint(newRights)&LongB {
-			b.hash ^= hash.HashTable.Castling[2]
-		}
-		if uint(oldRights)&LongB != uint(newRights)&LongB {
-			b.hash ^= hash.HashTable.Castling[3]
+// repetitions walks the position history backwards, looking only at positions
+// with the same side to move, and reports whether the current hash was found
+// at least count times.
+func (b *Board) repetitions(count int) bool {
+	n := len(b.history)
+	limit := min(int(b.HalfMoveClock), n-b.nullPly)
+
+	found := 0
+	// A position cannot repeat in fewer than four plies
+	for i := 4; i <= limit; i += 2 {
+		if b.history[n-i] == b.hash {
+			found++
+			if found >= count {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	// Switch side to move
-	b.hash ^= hash.HashTable.Side
+// IsFiftyMoveDraw reports whether the fifty-move rule applies, i.e. no pawn
+// move or capture has been made in the last 100 plies. A checkmate delivered
+// on the 100th ply still takes precedence over the draw.
+func (b *Board) IsFiftyMoveDraw() bool {
+	if b.HalfMoveClock < 100 {
+		return false
+	}
+	return !b.IsCheckmate()
 }
 
 // Hash method to get current hash
@@ -686,10 +708,8 @@ func (b *Board) Hash() uint64 {
 
 // GetPieceAt returns the piece at a given square, if it exists
 func (b *Board) GetPieceAt(square int) int {
-	for piece := WP; piece <= BK; piece++ {
-		if b.Bitboards[piece].Test(square) {
-			return piece
-		}
+	if !b.Occupancies[color.BOTH].Test(square) {
+		return Empty
 	}
-	return Empty
+	return b.pieces[square]
 }
@@ -7,12 +7,14 @@ package board
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/Tecu23/argov2/internal/hash"
 	"github.com/Tecu23/argov2/pkg/bitboard"
 	"github.com/Tecu23/argov2/pkg/color"
 	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
 	"github.com/Tecu23/argov2/pkg/util"
 )
 
@@ -173,6 +175,306 @@ func TestCopyAndTakeBack(t *testing.T) {
 	}
 }
 
+// TestCopyBoardConcurrent checks that copies of a board with room to grow its
+// history can make moves at the same time without sharing state. Run with
+// -race to see shared writes.
+func TestCopyBoardConcurrent(t *testing.T) {
+	b, err := ParseFEN(StartPosition)
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+	b.SetHistory(make([]uint64, 0, 64))
+
+	// Every copy shuffles other pieces, reaching other positions
+	shuffles := [][]string{
+		{"Nf3", "Nf6", "Ng1", "Ng8"},
+		{"Nc3", "Nc6", "Nb1", "Nb8"},
+		{"Nh3", "Nh6", "Ng1", "Ng8"},
+		{"Na3", "Na6", "Nb1", "Nb8"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(shuffles))
+	for _, shuffle := range shuffles {
+		wg.Add(1)
+		go func(cpy Board) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for _, san := range shuffle {
+					m, err := cpy.ParseSAN(san)
+					if err != nil || !cpy.MakeMove(m, AllMoves) {
+						errs <- fmt.Errorf("%v: failed to make %s: %v", shuffle, san, err)
+						return
+					}
+				}
+				if !cpy.IsRepetition() {
+					errs <- fmt.Errorf("%v: expected repetition after the shuffle", shuffle)
+					return
+				}
+				for range shuffle {
+					cpy.UnmakeMove()
+				}
+			}
+		}(b.CopyBoard())
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if b.IsRepetition() || b.Hash() != b.calculateHash() {
+		t.Error("Moves on the copies changed the original board")
+	}
+}
+
+func TestMakeUnmakeMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		depth int
+	}{
+		{"Kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 3},
+		{"Promotions", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 3},
+		{"En Passant", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 4},
+		{"Chess960", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN: %v", err)
+			}
+			walkMakeUnmake(t, &b, tt.depth)
+		})
+	}
+}
+
+// walkMakeUnmake makes and takes back every move down to the given depth and
+// checks the incremental state against a copy of the board and a full rebuild.
+func walkMakeUnmake(t *testing.T, b *Board, depth int) {
+	t.Helper()
+	if depth == 0 {
+		return
+	}
+
+	var moves move.List
+	b.GenerateMoves(&moves)
+
+	for _, mv := range moves.Moves() {
+		before := b.CopyBoard()
+
+		if b.MakeMove(mv, AllMoves) {
+			if b.Hash() != b.calculateHash() {
+				t.Fatalf("%s %s: incremental hash differs from calculated hash", before.FEN(), mv)
+			}
+			if err := verifyMailbox(b); err != nil {
+				t.Fatalf("%s %s: %v", before.FEN(), mv, err)
+			}
+			walkMakeUnmake(t, b, depth-1)
+			b.UnmakeMove()
+		}
+
+		if err := verifyBoardsMatch(b, &before); err != nil {
+			t.Fatalf("%s %s: after unmake: %v", before.FEN(), mv, err)
+		}
+		if b.Hash() != before.Hash() || b.FullMoveCounter != before.FullMoveCounter {
+			t.Fatalf("%s %s: hash or fullmove counter not restored", before.FEN(), mv)
+		}
+		if err := verifyMailbox(b); err != nil {
+			t.Fatalf("%s %s: after unmake: %v", before.FEN(), mv, err)
+		}
+	}
+}
+
+func TestUnmakeNullMove(t *testing.T) {
+	b, err := ParseFEN("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 3")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+	before := b.CopyBoard()
+
+	b.MakeNullMove()
+	if !b.IsAfterNullMove() || b.EnPassant != -1 {
+		t.Fatalf("null move not made")
+	}
+	b.UnmakeMove()
+
+	if err := verifyBoardsMatch(&b, &before); err != nil {
+		t.Errorf("after unmake: %v", err)
+	}
+	if b.Hash() != before.Hash() || b.IsAfterNullMove() {
+		t.Errorf("hash or null move state not restored")
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	b, err := ParseFEN(StartPosition)
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	shuffle := []string{"g1f3", "g8f6", "f3g1", "f6g8"}
+
+	for _, mv := range shuffle {
+		if b.IsRepetition() {
+			t.Fatalf("Unexpected repetition before %s", mv)
+		}
+		next, ok := b.ParseMove(mv)
+		if !ok {
+			t.Fatalf("Failed to make move: %s", mv)
+		}
+		b = next
+	}
+
+	if !b.IsRepetition() {
+		t.Error("Expected repetition after knights returned home")
+	}
+	if b.IsThreefoldRepetition() {
+		t.Error("Position occurred only twice, expected no threefold repetition")
+	}
+
+	for _, mv := range shuffle {
+		next, ok := b.ParseMove(mv)
+		if !ok {
+			t.Fatalf("Failed to make move: %s", mv)
+		}
+		b = next
+	}
+
+	if !b.IsThreefoldRepetition() {
+		t.Error("Expected threefold repetition")
+	}
+
+	// A pawn move is irreversible, so earlier positions no longer count
+	next, ok := b.ParseMove("e2e4")
+	if !ok {
+		t.Fatal("Failed to make move: e2e4")
+	}
+	if next.HalfMoveClock != 0 {
+		t.Errorf("Pawn move should reset the half move clock, got %d", next.HalfMoveClock)
+	}
+	if next.IsRepetition() {
+		t.Error("Unexpected repetition after pawn move")
+	}
+}
+
+func TestNullMoveRepetition(t *testing.T) {
+	b, err := ParseFEN("4k3/8/8/8/8/8/8/4K3 w - - 0 1")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	// White passes and then triangulates back to e1, reaching the starting
+	// position with White to move. Only the null move made that possible,
+	// so it must not count as a repetition.
+	b.MakeNullMove()
+	if !b.IsAfterNullMove() {
+		t.Error("Expected position to be marked as reached by a null move")
+	}
+	if b.Hash() != b.calculateHash() {
+		t.Error("Incremental hash does not match recalculated hash after null move")
+	}
+
+	for _, mv := range []string{"e8d8", "e1d1", "d8e8", "d1d2", "e8d8", "d2e1", "d8e8"} {
+		next, ok := b.ParseMove(mv)
+		if !ok {
+			t.Fatalf("Failed to make move: %s", mv)
+		}
+		b = next
+		if b.IsAfterNullMove() {
+			t.Errorf("Position after %s should not be marked as null move", mv)
+		}
+	}
+
+	if b.IsRepetition() {
+		t.Error("Repetition detection must not look past a null move")
+	}
+
+	// Positions after the null move still repeat normally
+	for _, mv := range []string{"e1d1", "e8d8", "d1e1", "d8e8"} {
+		next, ok := b.ParseMove(mv)
+		if !ok {
+			t.Fatalf("Failed to make move: %s", mv)
+		}
+		b = next
+	}
+	if !b.IsRepetition() {
+		t.Error("Expected repetition of a position reached after the null move")
+	}
+}
+
+func TestHasNonPawnMaterial(t *testing.T) {
+	b, err := ParseFEN("4k3/pppp4/8/8/8/8/4P3/4KB2 w - - 0 1")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	if !b.HasNonPawnMaterial(color.WHITE) {
+		t.Error("White has a bishop")
+	}
+	if b.HasNonPawnMaterial(color.BLACK) {
+		t.Error("Black has only king and pawns")
+	}
+}
+
+func TestFiftyMoveDraw(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		move     string
+		expected bool
+	}{
+		{
+			name:     "Quiet move reaches 100 plies",
+			fen:      "4k3/8/8/8/8/8/8/R3K3 w - - 99 80",
+			move:     "a1a2",
+			expected: true,
+		},
+		{
+			name:     "Capture resets the counter",
+			fen:      "4k3/8/8/8/8/8/r7/R3K3 w - - 99 80",
+			move:     "a1a2",
+			expected: false,
+		},
+		{
+			name:     "Pawn move resets the counter",
+			fen:      "4k3/8/8/8/8/8/P7/4K3 w - - 99 80",
+			move:     "a2a3",
+			expected: false,
+		},
+		{
+			name:     "Checkmate takes precedence",
+			fen:      "7k/8/6K1/8/8/8/8/R7 w - - 99 80",
+			move:     "a1a8",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			if b.IsFiftyMoveDraw() {
+				t.Fatal("Unexpected fifty-move draw before the move")
+			}
+
+			next, ok := b.ParseMove(tt.move)
+			if !ok {
+				t.Fatalf("Failed to make move: %s", tt.move)
+			}
+
+			if got := next.IsFiftyMoveDraw(); got != tt.expected {
+				t.Errorf("IsFiftyMoveDraw() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 // Helper function to verify if two board positions match
 func verifyBoardsMatch(b *Board, copy *Board) error {
 	for i := range b.Bitboards {
@@ -200,6 +502,22 @@ func verifyBoardsMatch(b *Board, copy *Board) error {
 	return nil
 }
 
+// Helper function to verify the mailbox agrees with the piece bitboards
+func verifyMailbox(b *Board) error {
+	for sq := 0; sq < 64; sq++ {
+		want := Empty
+		for piece := WP; piece <= BK; piece++ {
+			if b.Bitboards[piece].Test(sq) {
+				want = piece
+			}
+		}
+		if got := b.GetPieceAt(sq); got != want {
+			return fmt.Errorf("square %d holds %d, mailbox has %d", sq, want, got)
+		}
+	}
+	return nil
+}
+
 // Helper function to verify if two boards are different
 func boardsAreDifferent(b *Board, copy *Board) bool {
 	return verifyBoardsMatch(b, copy) != nil
@@ -6,45 +6,39 @@
 package board
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/Tecu23/argov2/pkg/bitboard"
+	"github.com/Tecu23/argov2/pkg/color"
 	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
 )
 
-// CastlingRights is an array that helps update castling rights
-// when a certain square is moved from or to. Its values represent bit masks
-// that modify the current castling availability.
-var CastlingRights = []uint{
-	7, 15, 15, 15, 3, 15, 15, 11,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15,
-	13, 15, 15, 15, 12, 15, 15, 14,
-}
-
 /*
-	castling  move    in       in
-	   right update   binary   decimal
-
-king & rooks didn't move:       1111 & 1111  =  1111    15
+	Castling rights are kept as four bits, one for each side and direction.
+	Short castling uses the rook on the king's h-file side and long castling
+	the rook on its a-file side, so the same bits describe both standard
+	chess and Chess960 (Fischer Random) where king and rooks may start on
+	any file. The start square of each castling rook is kept on the board.
 
-	       white king moved:       1111 & 1100  =  1100    12
-	white king's rook moved:       1111 & 1110  =  1110    14
-   white queen's rook moved:       1111 & 1101  =  1101    13
-
-	       black king moved:       1111 & 0011  =  0011    3
-	black king's rook moved:       1111 & 1011  =  1011    11
-   black queen's rook moved:       1111 & 0111  =  0111    7
+	bit   right   standard rook
+	0001  ShortW  h1
+	0010  LongW   a1
+	0100  ShortB  h8
+	1000  LongB   a8
 */
 
+// defaultCastlingRooks holds the rook start squares of standard chess, indexed
+// by the castling right bit position (ShortW, LongW, ShortB, LongB).
+var defaultCastlingRooks = [4]int{H1, A1, H8, A8}
+
 // Castlings represents the current castling rights as a bitfield.
 type Castlings uint
 
 // ParseCastlings parses the FEN castling substring (e.g., "KQkq") and returns
-// the corresponding castling rights bitfield.
+// the corresponding castling rights bitfield. It only understands standard
+// chess notation; ParseFEN also accepts Shredder-FEN and X-FEN fields.
 func ParseCastlings(fenCastl string) Castlings {
 	c := uint(0)
 
@@ -88,3 +82,257 @@ func (c Castlings) String() string {
 	}
 	return flags
 }
+
+// castlingIndex returns the castling right bit position for a side and direction.
+func castlingIndex(side color.Color, queenSide bool) int {
+	idx := int(side) * 2
+	if queenSide {
+		idx++
+	}
+	return idx
+}
+
+// backRank returns the square of the a-file on the back rank of the given side.
+func backRank(side color.Color) int {
+	if side == color.WHITE {
+		return A1
+	}
+	return A8
+}
+
+// CastlingSquares returns the king and rook start and destination squares of
+// a castling move. The king always ends on the g- or c-file and the rook on the
+// f- or d-file, wherever they started.
+func (b *Board) CastlingSquares(m move.Move) (kingFrom, kingTo, rookFrom, rookTo int) {
+	side := color.Color(m.GetMovingPieceColor())
+	base := backRank(side)
+	queenSide := m.IsQueenCastle()
+
+	kingTo, rookTo = base+6, base+5
+	if queenSide {
+		kingTo, rookTo = base+2, base+3
+	}
+
+	return m.GetSourceSquare(), kingTo, b.castlingRooks[castlingIndex(side, queenSide)], rookTo
+}
+
+// castlingsAfter returns the castling rights left after the given piece moved
+// from src to tgt. Moving the king loses both rights of its side, while moving
+// or capturing a castling rook loses the matching right.
+func (b *Board) castlingsAfter(piece, src, tgt int) Castlings {
+	c := uint(b.Castlings)
+	if c == 0 {
+		return b.Castlings
+	}
+
+	switch piece {
+	case WK:
+		c &^= ShortW | LongW
+	case BK:
+		c &^= ShortB | LongB
+	}
+
+	for idx, rookSq := range b.castlingRooks {
+		if src == rookSq || tgt == rookSq {
+			c &^= 1 << idx
+		}
+	}
+
+	return Castlings(c)
+}
+
+// generateCastlings adds the castling moves of the given side. Every square
+// the king and rook travel over must be empty apart from the king and rook
+// themselves, and the king may not start in, pass through or land in check.
+// In Chess960 mode the move targets the rook square (king takes rook).
+func (b *Board) generateCastlings(list *move.List, side color.Color) {
+	king := WK
+	if side == color.BLACK {
+		king = BK
+	}
+
+	kingBB := b.Bitboards[king]
+	kingSq := kingBB.FirstOne()
+	if kingSq == 64 {
+		return
+	}
+
+	for _, queenSide := range [2]bool{false, true} {
+		idx := castlingIndex(side, queenSide)
+		if uint(b.Castlings)&(1<<idx) == 0 {
+			continue
+		}
+
+		m := move.EncodeMove(kingSq, kingSq, king, move.KingCastle, 0)
+		if queenSide {
+			m = move.EncodeMove(kingSq, kingSq, king, move.QueenCastle, 0)
+		}
+		_, kingTo, rookSq, rookTo := b.CastlingSquares(m)
+
+		occupied := b.Occupancies[color.BOTH]
+		occupied.Clear(kingSq)
+		occupied.Clear(rookSq)
+
+		if !squaresEmpty(occupied, kingSq, kingTo) || !squaresEmpty(occupied, rookSq, rookTo) {
+			continue
+		}
+		if b.squaresAttacked(kingSq, kingTo, side.Opp()) {
+			continue
+		}
+
+		target := kingTo
+		if b.Chess960 {
+			target = rookSq
+		}
+
+		t := move.KingCastle
+		if queenSide {
+			t = move.QueenCastle
+		}
+		list.Add(move.EncodeMove(kingSq, target, king, t, 0))
+	}
+}
+
+// squaresEmpty reports whether all squares between from and to (inclusive) on
+// the same rank are empty in the given occupancy.
+func squaresEmpty(occupied bitboard.Bitboard, from, to int) bool {
+	lo, hi := min(from, to), max(from, to)
+	for sq := lo; sq <= hi; sq++ {
+		if occupied.Test(sq) {
+			return false
+		}
+	}
+	return true
+}
+
+// squaresAttacked reports whether any square between from and to (inclusive)
+// on the same rank is attacked by the given side.
+func (b *Board) squaresAttacked(from, to int, side color.Color) bool {
+	lo, hi := min(from, to), max(from, to)
+	for sq := lo; sq <= hi; sq++ {
+		if b.IsSquareAttacked(sq, side) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCastlingField parses the FEN castling field. Besides the standard KQkq
+// letters it accepts Shredder-FEN rook files (e.g. "HAha") and X-FEN, where
+// K and Q refer to the outermost rook on each side of the king. Unknown
+// characters, repeated rights and rights without a matching king and rook are
+// rejected. Castling with a king or rook off its standard square enables
+// Chess960 mode.
+func (b *Board) parseCastlingField(field string) error {
+	b.Castlings = 0
+	b.castlingRooks = defaultCastlingRooks
+
+	if field == "-" {
+		return nil
+	}
+
+	for _, char := range field {
+		side, king, rook := color.WHITE, WK, WR
+		lower := char
+		if char >= 'a' && char <= 'z' {
+			side, king, rook = color.BLACK, BK, BR
+		} else {
+			lower = char + 'a' - 'A'
+		}
+		base := backRank(side)
+
+		kingBB := b.Bitboards[king] & (bitboard.Bitboard(0xFF) << uint(base))
+		kingSq := kingBB.FirstOne()
+		if kingSq == 64 {
+			return fmt.Errorf("parse fen failed: castling right %c without king on the back rank", char)
+		}
+		kingFile := kingSq - base
+
+		rookSq := -1
+		switch {
+		case lower == 'k':
+			for file := 7; file > kingFile && rookSq == -1; file-- {
+				if b.Bitboards[rook].Test(base + file) {
+					rookSq = base + file
+				}
+			}
+		case lower == 'q':
+			for file := 0; file < kingFile && rookSq == -1; file++ {
+				if b.Bitboards[rook].Test(base + file) {
+					rookSq = base + file
+				}
+			}
+		case lower >= 'a' && lower <= 'h':
+			if file := int(lower - 'a'); file != kingFile {
+				rookSq = base + file
+			}
+		default:
+			return fmt.Errorf("parse fen failed: %s invalid castling rights", field)
+		}
+
+		if rookSq == -1 || !b.Bitboards[rook].Test(rookSq) {
+			return fmt.Errorf("parse fen failed: castling right %c without a rook to castle with", char)
+		}
+
+		idx := castlingIndex(side, rookSq < kingSq)
+		if uint(b.Castlings)&(1<<idx) != 0 {
+			return fmt.Errorf("parse fen failed: %s repeats a castling right", field)
+		}
+		b.Castlings |= Castlings(1 << idx)
+		b.castlingRooks[idx] = rookSq
+
+		if kingFile != 4 || rookSq != defaultCastlingRooks[idx] {
+			b.Chess960 = true
+		}
+	}
+
+	return nil
+}
+
+// castlingString returns the castling field of the FEN. Standard positions use
+// KQkq; in Chess960 a rook that is not the outermost one on its side of the
+// king is written by its file letter (X-FEN).
+func (b *Board) castlingString() string {
+	if b.Castlings == 0 {
+		return "-"
+	}
+
+	sb := &strings.Builder{}
+	for idx, letter := range "KQkq" {
+		if uint(b.Castlings)&(1<<idx) == 0 {
+			continue
+		}
+
+		side, rook := color.WHITE, WR
+		if idx >= 2 {
+			side, rook = color.BLACK, BR
+		}
+		base := backRank(side)
+		rookSq := b.castlingRooks[idx]
+
+		// Look for another rook further out on the same side of the king
+		outermost := true
+		step := 1
+		if idx%2 == 1 {
+			step = -1
+		}
+		for sq := rookSq + step; sq >= base && sq < base+8; sq += step {
+			if b.Bitboards[rook].Test(sq) {
+				outermost = false
+			}
+		}
+
+		if outermost {
+			sb.WriteRune(letter)
+			continue
+		}
+
+		file := byte('a' + rookSq - base)
+		if side == color.WHITE {
+			file -= 'a' - 'A'
+		}
+		sb.WriteByte(file)
+	}
+
+	return sb.String()
+}
@@ -4,3 +4,147 @@
 // Package board contains the board representation and all board helper functions.
 // This package will handle move generation
 package board
+
+import (
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+func TestChess960Perft(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		depth    int
+		expected int64
+	}{
+		{"Position 1 Depth 3", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 3, 12189},
+		{"Position 1 Depth 4", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 4, 326672},
+		{"Position 2 Depth 3", "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9", 3, 18002},
+		{"Position 2 Depth 4", "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9", 4, 667366},
+		{"Position 3 Depth 4", "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9", 4, 273318},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+			if !b.Chess960 {
+				t.Fatal("Expected Chess960 mode to be detected")
+			}
+
+			if got := PerftDriver(&b, tt.depth); got != tt.expected {
+				t.Errorf("Perft(%d) = %d, want %d", tt.depth, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChess960CastlingField(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		chess960 bool
+		expected string
+	}{
+		{"Standard", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", false, "KQkq"},
+		{"Standard Shredder", "r3k2r/8/8/8/8/8/8/R3K2R w HAha - 0 1", false, "KQkq"},
+		{"Shredder Outermost Rooks", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w GBgb - 0 1", true, "KQkq"},
+		{"X-FEN Outermost Rooks", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w KQkq - 0 1", true, "KQkq"},
+		{"Inner Rook", "4k3/8/8/8/8/8/8/4KRR1 w F - 0 1", true, "F"},
+		{"Inner Black Rook", "rr2k3/8/8/8/8/8/8/4K3 b b - 0 1", true, "b"},
+		{"King On F File", "rk5r/8/8/8/8/8/8/RK5R w KQkq - 0 1", true, "KQkq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			if b.Chess960 != tt.chess960 {
+				t.Errorf("Chess960 = %v, want %v", b.Chess960, tt.chess960)
+			}
+			if got := b.castlingString(); got != tt.expected {
+				t.Errorf("Castling field = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChess960Castling(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		move     string
+		expected string
+	}{
+		{
+			"King Takes Adjacent Rook",
+			"4k3/8/8/8/8/8/8/RK5R w HA - 0 1",
+			"b1a1",
+			"4k3/8/8/8/8/8/8/2KR3R b - - 1 1",
+		},
+		{
+			"King Already On Target",
+			"4k3/8/8/8/8/8/8/R5KR w HA - 0 1",
+			"g1h1",
+			"4k3/8/8/8/8/8/8/R4RK1 b - - 1 1",
+		},
+		{
+			"Rook Already On Target",
+			"4k3/8/8/8/8/8/8/1R1K4 w B - 0 1",
+			"d1b1",
+			"4k3/8/8/8/8/8/8/2KR4 b - - 1 1",
+		},
+		{
+			"Black Long Castle",
+			"1r2k1r1/8/8/8/8/8/8/4K3 b gb - 0 1",
+			"e8b8",
+			"2kr2r1/8/8/8/8/8/8/4K3 w - - 1 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			mv := findMove(t, &b, tt.move)
+			if !mv.IsCastle() {
+				t.Fatalf("Expected %s to be a castling move", tt.move)
+			}
+			if !b.MakeMove(mv, AllMoves) {
+				t.Fatalf("Castling %s was rejected", tt.move)
+			}
+			if got := b.FEN(); got != tt.expected {
+				t.Errorf("After %s: got %s, want %s", tt.move, got, tt.expected)
+			}
+			if b.Hash() != b.calculateHash() {
+				t.Error("Incremental hash does not match recalculated hash")
+			}
+		})
+	}
+}
+
+func TestChess960CastlingThroughCheck(t *testing.T) {
+	// The king on b1 would cross c1-g1, which the black rook on e8 attacks
+	b, err := ParseFEN("k3r3/8/8/8/8/8/8/1K5R w H - 0 1")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	var moves move.List
+	b.GenerateMoves(&moves)
+
+	for _, mv := range moves.Moves() {
+		if mv.IsCastle() {
+			t.Errorf("Unexpected castling move %s", mv)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tecu23/argov2/pkg/color"
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// ParseFEN sets the board state according to a given FEN string.
+// It places pieces, sets side to move, castling rights, en passant square and
+// the move counters. The halfmove clock and fullmove counter are optional and
+// default to 0 and 1. Every field is validated and the resulting position must
+// pass Validate, otherwise a descriptive error is returned.
+// rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1
+func ParseFEN(FEN string) (Board, error) {
+	b := Board{}
+	b.Reset()
+
+	fields := strings.Fields(FEN)
+	if len(fields) < 4 {
+		return Board{}, fmt.Errorf(
+			"parse fen failed: expected at least 4 fields, got %d",
+			len(fields),
+		)
+	}
+	if len(fields) > 6 {
+		return Board{}, fmt.Errorf(
+			"parse fen failed: expected at most 6 fields, got %d",
+			len(fields),
+		)
+	}
+
+	// Parse the ranks from top (rank 8) to bottom (rank 1)
+	if err := b.parsePlacement(fields[0]); err != nil {
+		return Board{}, err
+	}
+
+	// Set side to move
+	switch fields[1] {
+	case "w":
+		b.SideToMove = color.WHITE
+	case "b":
+		b.SideToMove = color.BLACK
+	default:
+		return Board{}, fmt.Errorf("parse fen failed: %s invalid side to move color", fields[1])
+	}
+
+	// Set castling rights
+	if err := b.parseCastlingField(fields[2]); err != nil {
+		return Board{}, err
+	}
+
+	// Set en passant square
+	b.EnPassant = -1
+	if fields[3] != "-" {
+		sq, ok := util.Fen2Sq[fields[3]]
+		if !ok {
+			return Board{}, fmt.Errorf("parse fen failed: %s invalid en passant square", fields[3])
+		}
+		b.EnPassant = sq
+	}
+
+	// Set halfmove clock (for 50-move rule)
+	b.HalfMoveClock = 0
+	if len(fields) > 4 {
+		cnt, err := strconv.Atoi(fields[4])
+		if err != nil || cnt < 0 || cnt > 255 {
+			return Board{}, fmt.Errorf("parse fen failed: %s invalid halfmove clock", fields[4])
+		}
+		b.HalfMoveClock = uint8(cnt)
+	}
+
+	// Set fullmove counter
+	b.FullMoveCounter = 1
+	if len(fields) > 5 {
+		cnt, err := strconv.Atoi(fields[5])
+		if err != nil || cnt < 1 {
+			return Board{}, fmt.Errorf("parse fen failed: %s invalid fullmove counter", fields[5])
+		}
+		b.FullMoveCounter = cnt
+	}
+
+	if err := b.Validate(); err != nil {
+		return Board{}, fmt.Errorf("parse fen failed: %v", err)
+	}
+
+	b.hash = b.calculateHash()
+
+	return b, nil
+}
+
+// parsePlacement places the pieces described by the first FEN field on the board.
+func (b *Board) parsePlacement(placement string) error {
+	ranks := strings.Split(placement, "/")
+	if len(ranks) != 8 {
+		return fmt.Errorf("parse fen failed: expected 8 ranks, got %d", len(ranks))
+	}
+
+	for row, rank := range ranks {
+		file := 0
+		lastWasDigit := false
+
+		for _, char := range rank {
+			// If char is a digit, skip that many squares
+			if char >= '1' && char <= '8' {
+				if lastWasDigit {
+					return fmt.Errorf("parse fen failed: consecutive digits in rank %d", 8-row)
+				}
+				file += int(char - '0')
+				lastWasDigit = true
+			} else {
+				// Otherwise, it should be a piece character
+				piece := util.Fen2pc(string(char))
+				if piece == Empty {
+					return fmt.Errorf("parse fen failed: invalid piece %c in rank %d", char, 8-row)
+				}
+				if file < 8 {
+					b.SetSq(piece, row*8+file)
+				}
+				file++
+				lastWasDigit = false
+			}
+
+			if file > 8 {
+				return fmt.Errorf("parse fen failed: too many squares in rank %d", 8-row)
+			}
+		}
+
+		if file != 8 {
+			return fmt.Errorf("parse fen failed: too few squares in rank %d", 8-row)
+		}
+	}
+
+	return nil
+}
+
+// FEN returns the Forsyth-Edwards Notation of the current position.
+func (b *Board) FEN() string {
+	sb := &strings.Builder{}
+
+	// Piece placement from rank 8 down to rank 1
+	for row := 0; row < 8; row++ {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := b.GetPieceAt(row*8 + file)
+			if piece == Empty {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteByte(util.ASCIIPieces[piece])
+		}
+
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+		if row < 7 {
+			sb.WriteByte('/')
+		}
+	}
+
+	side := "w"
+	if b.SideToMove == color.BLACK {
+		side = "b"
+	}
+
+	enPassant := "-"
+	if b.EnPassant != -1 {
+		enPassant = util.Sq2Fen[b.EnPassant]
+	}
+
+	fmt.Fprintf(
+		sb,
+		" %s %s %s %d %d",
+		side,
+		b.castlingString(),
+		enPassant,
+		b.HalfMoveClock,
+		b.FullMoveCounter,
+	)
+
+	return sb.String()
+}
+
+// Validate checks that the position is legal: each side has exactly one king,
+// there are no pawns on the first or last rank, piece counts are possible,
+// the side that just moved is not in check, and the castling rights and en
+// passant square are consistent with the piece placement.
+func (b *Board) Validate() error {
+	if n := b.Bitboards[WK].Count(); n != 1 {
+		return fmt.Errorf("white must have exactly one king, found %d", n)
+	}
+	if n := b.Bitboards[BK].Count(); n != 1 {
+		return fmt.Errorf("black must have exactly one king, found %d", n)
+	}
+
+	// Rank 8 is squares A8-H8 (0-7) and rank 1 is A1-H1 (56-63)
+	for sq := A8; sq <= H8; sq++ {
+		if b.Bitboards[WP].Test(sq) || b.Bitboards[BP].Test(sq) {
+			return fmt.Errorf("pawn on the last rank at %s", util.Sq2Fen[sq])
+		}
+	}
+	for sq := A1; sq <= H1; sq++ {
+		if b.Bitboards[WP].Test(sq) || b.Bitboards[BP].Test(sq) {
+			return fmt.Errorf("pawn on the first rank at %s", util.Sq2Fen[sq])
+		}
+	}
+
+	if b.Bitboards[WP].Count() > 8 || b.Bitboards[BP].Count() > 8 {
+		return fmt.Errorf("more than 8 pawns for one side")
+	}
+	if b.Occupancies[color.WHITE].Count() > 16 || b.Occupancies[color.BLACK].Count() > 16 {
+		return fmt.Errorf("more than 16 pieces for one side")
+	}
+
+	// The side that is not to move cannot be in check
+	opp := b.SideToMove.Opp()
+	kingBB := b.Bitboards[WK]
+	if opp == color.BLACK {
+		kingBB = b.Bitboards[BK]
+	}
+	if b.IsSquareAttacked(kingBB.FirstOne(), b.SideToMove) {
+		return fmt.Errorf("side not to move is in check")
+	}
+
+	if err := b.validateCastlings(); err != nil {
+		return err
+	}
+
+	return b.validateEnPassant()
+}
+
+// validateCastlings makes sure every castling right has its king on the back
+// rank and its rook on the recorded start square, on the matching side of the king.
+func (b *Board) validateCastlings() error {
+	for idx, notation := range "KQkq" {
+		if uint(b.Castlings)&(1<<idx) == 0 {
+			continue
+		}
+
+		side, king, rook := color.WHITE, WK, WR
+		if idx >= 2 {
+			side, king, rook = color.BLACK, BK, BR
+		}
+		base := backRank(side)
+
+		kingBB := b.Bitboards[king]
+		kingSq := kingBB.FirstOne()
+		rookSq := b.castlingRooks[idx]
+
+		if kingSq < base || kingSq >= base+8 || !b.Bitboards[rook].Test(rookSq) {
+			return fmt.Errorf("castling right %c without king and rook on their initial squares", notation)
+		}
+		if (idx%2 == 0) != (rookSq > kingSq) {
+			return fmt.Errorf("castling right %c with the rook on the wrong side of the king", notation)
+		}
+	}
+
+	return nil
+}
+
+// validateEnPassant makes sure the en passant square could have been created by
+// a double pawn push of the side that just moved.
+func (b *Board) validateEnPassant() error {
+	if b.EnPassant == -1 {
+		return nil
+	}
+
+	ep := b.EnPassant
+	if ep < 0 || ep > H1 {
+		return fmt.Errorf("invalid en passant square %d", ep)
+	}
+
+	// White to move: black just pushed a pawn from rank 7 to rank 5, so the
+	// en passant square is on rank 6. Black to move is the mirror case.
+	rank, pawnSq, originSq, pawn := 2, ep+8, ep-8, BP
+	if b.SideToMove == color.BLACK {
+		rank, pawnSq, originSq, pawn = 5, ep-8, ep+8, WP
+	}
+
+	if ep/8 != rank {
+		return fmt.Errorf("en passant square %s on the wrong rank", util.Sq2Fen[ep])
+	}
+	if b.Occupancies[color.BOTH].Test(ep) || b.Occupancies[color.BOTH].Test(originSq) {
+		return fmt.Errorf("en passant square %s is not empty behind the pawn", util.Sq2Fen[ep])
+	}
+	if !b.Bitboards[pawn].Test(pawnSq) {
+		return fmt.Errorf("en passant square %s without a pawn that just moved", util.Sq2Fen[ep])
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"testing"
+
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+func TestFENRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"Initial Position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"},
+		{"Position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1"},
+		{"Position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1"},
+		{"Position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8"},
+		{"Position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10"},
+		{"White En Passant", "rnbqkbnr/ppp2ppp/4p3/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3"},
+		{"Black En Passant", "rnbqkbnr/pppp1ppp/8/8/3Pp3/8/PPP1PPPP/RNBQKBNR b KQkq d3 0 2"},
+		{"Partial Castling", "r3k3/8/8/8/8/8/8/4K2R b Kq - 12 40"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			if got := b.FEN(); got != tt.fen {
+				t.Errorf("FEN round trip failed: got %s, want %s", got, tt.fen)
+			}
+		})
+	}
+}
+
+func TestFENAfterMoves(t *testing.T) {
+	b, err := ParseFEN(StartPosition)
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	expected := []struct {
+		move string
+		fen  string
+	}{
+		{"e2e4", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},
+		{"c7c5", "rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2"},
+		{"g1f3", "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"},
+		{"b8c6", "r1bqkbnr/pp1ppppp/2n5/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3"},
+		{"f1b5", "r1bqkbnr/pp1ppppp/2n5/1Bp5/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq - 3 3"},
+		{"g8f6", "r1bqkb1r/pp1ppppp/2n2n2/1Bp5/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4"},
+		{"e1g1", "r1bqkb1r/pp1ppppp/2n2n2/1Bp5/4P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 5 4"},
+	}
+
+	for _, step := range expected {
+		next, ok := b.ParseMove(step.move)
+		if !ok {
+			t.Fatalf("Failed to make move: %s", step.move)
+		}
+		b = next
+
+		if got := b.FEN(); got != step.fen {
+			t.Errorf("After %s: got %s, want %s", step.move, got, step.fen)
+		}
+	}
+}
+
+func TestParseFENDefaults(t *testing.T) {
+	b, err := ParseFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN without move counters: %v", err)
+	}
+
+	if b.HalfMoveClock != 0 || b.FullMoveCounter != 1 {
+		t.Errorf(
+			"Unexpected move counters: halfmove %d, fullmove %d",
+			b.HalfMoveClock,
+			b.FullMoveCounter,
+		)
+	}
+}
+
+func TestResetFEN(t *testing.T) {
+	var b Board
+	b.Reset()
+
+	if got, want := b.FEN(), "8/8/8/8/8/8/8/8 w - - 0 1"; got != want {
+		t.Errorf("FEN of a reset board: got %s, want %s", got, want)
+	}
+}
+
+func TestParseFENErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"Empty String", ""},
+		{"Placement Only", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR"},
+		{"Too Many Fields", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 extra"},
+		{"Seven Ranks", "rnbqkbnr/pppppppp/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Short Rank", "rnbqkbnr/ppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Long Rank", "rnbqkbnr/ppppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Consecutive Digits", "rnbqkbnr/pppppppp/44/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Invalid Piece", "rnbqkbnr/pppppppp/8/8/3X4/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"Invalid Side", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1"},
+		{"Invalid Castling", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkx - 0 1"},
+		{"Repeated Castling", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KKkq - 0 1"},
+		{"Invalid En Passant", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq z9 0 1"},
+		{"Invalid Halfmove", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1"},
+		{"Negative Halfmove", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - -1 1"},
+		{"Invalid Fullmove", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 x"},
+		{"Zero Fullmove", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseFEN(tt.fen); err == nil {
+				t.Errorf("Expected error for FEN %q", tt.fen)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		valid bool
+	}{
+		{"Initial Position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", true},
+		{"No White King", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQ1BNR w kq - 0 1", false},
+		{"Extra Black King", "rnbqkbnr/pppppppp/8/8/3k4/8/PPPPPPPP/RNBQKBNR w KQ - 0 1", false},
+		{"Pawn On Rank 8", "P3k3/8/8/8/8/8/8/4K3 w - - 0 1", false},
+		{"Pawn On Rank 1", "4k3/8/8/8/8/8/8/p3K3 w - - 0 1", false},
+		{"Too Many Pawns", "4k3/8/8/8/8/P7/PPPPPPPP/4K3 w - - 0 1", false},
+		{"Side Not To Move In Check", "4k3/8/8/8/8/8/4R3/4K3 w - - 0 1", false},
+		{"Side To Move In Check", "4k3/8/8/8/8/8/4r3/4K3 w - - 0 1", true},
+		{"Castling Without Rook", "4k3/8/8/8/8/8/8/4K3 w K - 0 1", false},
+		{"Castling With Moved King", "4k3/8/8/8/8/8/4K3/7R w K - 0 1", false},
+		{"Castling File Without Rook", "4k3/8/8/8/8/8/8/R3K3 w H - 0 1", false},
+		{"En Passant Wrong Rank", "4k3/8/8/8/3pP3/8/8/4K3 w - d4 0 1", false},
+		{"En Passant Without Pawn", "4k3/8/8/8/8/8/8/4K3 w - d6 0 1", false},
+		{"En Passant Occupied Origin", "4k3/3r4/8/3pP3/8/8/8/4K3 w - d6 0 1", false},
+		{"En Passant Valid", "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFEN(tt.fen)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid position, got error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected invalid position, got no error")
+			}
+		})
+	}
+}
@@ -13,881 +13,203 @@ import (
 	"github.com/Tecu23/argov2/pkg/move"
 )
 
-// TODO: Split this method into captures/quiet moves for all pieces to debug more easily
-// TODO: Fix Issue where a black pawn could capture a black king ???????
+// genKind selects which moves the generator produces.
+type genKind uint8
 
-// GenerateMoves generates all pseudo-legal moves for the current board position and
-// stores them in movelist. It handles pawns, knights, bishops, rooks, queens, kings,
-// and also pawn promotions, en passant, castling, etc.
-//
-// This is a large function with complex logic, so comments are only at a high level.
-//
-// Steps (high-level):
-// 1. Identify side to move.
-// 2. For each piece type of that color, find all pieces on the board.
-// 3. Generate moves according to the piece movement rules and occupancy of the board.
-// 4. For pawns: handle one and two-step advances, captures, en passant, and promotions.
-// 5. For kings: handle castling if available and king moves.
-// 6. For knights, bishops, rooks, queens: use precomputed attack tables for sliding pieces and direct attacks for knights/king.
-// 7. Add each valid generated move to movelist. This function doesn't check for check legality - that is done in MakeMove.
+const (
+	genCaptures genKind = 1 << iota // Captures, including en passant and capturing promotions
+	genQuiets                       // Everything else, including quiet promotions and castling
 
-// source, target, piece, promoted, captured, captureFlag, doublePush, enpassant, castlingFlag, castlingType int,
-func (b *Board) GenerateMoves() []move.Move {
-	result := make([]move.Move, 0, 10)
-	sourceSq, targetSq := 0, 0
-	bitboard, attcks := bitboard.Bitboard(0), bitboard.Bitboard(0)
-
-	for piece := WP; piece <= BK; piece++ {
-		bitboard = b.Bitboards[piece]
-
-		// generate pawns and castling moves depending of size
-		if b.SideToMove == color.WHITE {
-			if piece == WP {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-					targetSq = sourceSq - 8
-
-					// quiet pawn moves
-					if !(targetSq < A8) && !b.Occupancies[color.BOTH].Test(targetSq) {
-						// pawn promotion
-						if sourceSq >= A7 && sourceSq <= H7 {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.QueenPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.RookPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.BishopPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.KnightPromotionCapture,
-									0,
-								),
-							)
-						} else {
-
-							// one square ahead move
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-
-							// two square ahead move
-							if (sourceSq >= A2 && sourceSq <= H2) && !b.Occupancies[color.BOTH].Test(targetSq-8) {
-								result = append(
-									result,
-									move.EncodeMove(sourceSq, targetSq-8, piece, move.DoublePawnPush, 0),
-								)
-							}
-						}
-					}
-
-					// init pawn attacks bb
-					attcks = attacks.PawnAttacks[color.WHITE][sourceSq] & b.Occupancies[color.BLACK]
-
-					// generate pawn captures
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-						captured := b.GetPieceAt(targetSq)
-
-						if sourceSq >= A7 && sourceSq <= H7 {
-
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.QueenPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.RookPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.BishopPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.KnightPromotionCapture,
-									captured,
-								),
-							)
-						} else {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-
-					// generate EnPassant captures
-					if b.EnPassant != -1 {
-						enpassantAttacks := attacks.PawnAttacks[color.WHITE][sourceSq] & (1 << b.EnPassant)
-
-						// check enpassant capture
-						if enpassantAttacks != 0 {
-							// init enpassant capture target square
-							targetSq := enpassantAttacks.FirstOne()
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.EnPassant, BP),
-							)
-						}
-					}
-				}
-			}
-			// Castlings moves
-			if piece == WK {
-				// King side castling is available
-				if uint(b.Castlings)&ShortW != 0 {
-					// make sure square between king and king's rook are empty
-					if !b.Occupancies[color.BOTH].Test(F1) &&
-						!b.Occupancies[color.BOTH].Test(G1) {
-						// make sure king and the f1 square are not under attack
-						if !b.IsSquareAttacked(E1, color.BLACK) &&
-							!b.IsSquareAttacked(F1, color.BLACK) {
-							result = append(
-								result,
-								move.EncodeMove(E1, G1, piece, move.KingCastle, 0),
-							)
-						}
-					}
-				}
-
-				// Queen side castling is available
-				if uint(b.Castlings)&LongW != 0 {
-					// make sure square between king and queens's rook are empty
-					if !b.Occupancies[color.BOTH].Test(D1) &&
-						!b.Occupancies[color.BOTH].Test(C1) &&
-						!b.Occupancies[color.BOTH].Test(B1) {
-						// make sure king and the f1 square are not under attack
-						if !b.IsSquareAttacked(E1, color.BLACK) &&
-							!b.IsSquareAttacked(D1, color.BLACK) {
-							result = append(
-								result,
-								move.EncodeMove(E1, C1, piece, move.QueenCastle, 0),
-							)
-						}
-					}
-				}
-			}
-
-		} else {
-			if piece == BP {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-					targetSq = sourceSq + 8
-
-					// quiet pawn moves
-					if !(targetSq < 0 || targetSq > H1) && !b.Occupancies[color.BOTH].Test(targetSq) {
-						// pawn promotion
-						if sourceSq >= A2 && sourceSq <= H2 {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.QueenPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.RookPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.BishopPromotion, 0),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.KnightPromotion, 0),
-							)
-						} else {
-							// one square ahead move
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-
-							// two square ahead move
-							if (sourceSq >= A7 && sourceSq <= H7) && !b.Occupancies[color.BOTH].Test(targetSq+8) {
-								result = append(
-									result,
-									move.EncodeMove(sourceSq, targetSq+8, piece, move.DoublePawnPush, 0),
-								)
-							}
-						}
-					}
-
-					// init pawn attacks bb
-					attcks = attacks.PawnAttacks[color.BLACK][sourceSq] & b.Occupancies[color.WHITE]
-
-					// generate pawn captures
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						captured := b.GetPieceAt(targetSq)
-						if sourceSq >= A2 && sourceSq <= H2 {
-
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.QueenPromotionCapture, captured),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.RookPromotionCapture, captured),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.BishopPromotionCapture, captured),
-							)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.KnightPromotionCapture, captured),
-							)
-						} else {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-
-					// generate EnPassant captures
-					if b.EnPassant != -1 {
-						enpassantAttacks := attacks.PawnAttacks[color.BLACK][sourceSq] & (1 << b.EnPassant)
-
-						// check enpassant capture
-						if enpassantAttacks != 0 {
-							// init enpassant capture target square
-							targetSq := enpassantAttacks.FirstOne()
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.EnPassant, WP),
-							)
-						}
-					}
-				}
-			}
-
-			// Castlings moves
-			if piece == BK {
-				// King side castling is available
-				if uint(b.Castlings)&ShortB != 0 {
-					// make sure square between king and king's rook are empty
-					if !b.Occupancies[color.BOTH].Test(F8) && !b.Occupancies[color.BOTH].Test(G8) {
-						// make sure king and the f1 square are not under attack
-						if !b.IsSquareAttacked(E8, color.WHITE) && !b.IsSquareAttacked(F8, color.WHITE) {
-							result = append(
-								result,
-								move.EncodeMove(E8, G8, piece, move.KingCastle, 0),
-							)
-						}
-					}
-				}
-
-				// Queen side castling is available
-				if uint(b.Castlings)&LongB != 0 {
-					// make sure square between king and queens's rook are empty
-					if !b.Occupancies[color.BOTH].Test(D8) && !b.Occupancies[color.BOTH].Test(C8) &&
-						!b.Occupancies[color.BOTH].Test(B8) {
-						// make sure king and the f1 square are not under attack
-						if !b.IsSquareAttacked(E8, color.WHITE) && !b.IsSquareAttacked(D8, color.WHITE) {
-							result = append(
-								result,
-								move.EncodeMove(E8, C8, piece, move.QueenCastle, 0),
-							)
-						}
-					}
-				}
-			}
-		}
-
-		// generate knight moves
-		if b.SideToMove == color.WHITE {
-			if piece == WN {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.KnightAttacks[sourceSq] & (^b.Occupancies[color.WHITE])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.BLACK].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		} else {
-			if piece == BN {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.KnightAttacks[sourceSq] & (^b.Occupancies[color.BLACK])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.WHITE].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		}
-
-		// generate bihop moves
-		if b.SideToMove == color.WHITE {
-			if piece == WB {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.GetBishopAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & (^b.Occupancies[color.WHITE])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.BLACK].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		} else {
-			if piece == BB {
-				for bitboard != 0 {
-
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.GetBishopAttacks(sourceSq, b.Occupancies[color.BOTH]) & (^b.Occupancies[color.BLACK])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.WHITE].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		}
-
-		// generate rook moves
-		if b.SideToMove == color.WHITE {
-			if piece == WR {
-				for bitboard != 0 {
-
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.GetRookAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & (^b.Occupancies[color.WHITE])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.BLACK].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		} else {
-			if piece == BR {
-				for bitboard != 0 {
-
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.GetRookAttacks(sourceSq, b.Occupancies[color.BOTH]) & (^b.Occupancies[color.BLACK])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-
-						if !b.Occupancies[color.WHITE].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		}
-
-		// generate queen moves
-		if b.SideToMove == color.WHITE {
-			if piece == WQ {
-				for bitboard != 0 {
+	genAll = genCaptures | genQuiets
+)
 
-					sourceSq = bitboard.FirstOne()
+const allSquares = ^bitboard.Bitboard(0)
 
-					// init piece attacks
-					attcks = attacks.GetQueenAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & (^b.Occupancies[color.WHITE])
+// promotionTypes and capturePromotionTypes list the promotions in the order
+// they are generated, best first.
+var (
+	promotionTypes = [4]move.Type{
+		move.QueenPromotion,
+		move.RookPromotion,
+		move.BishopPromotion,
+		move.KnightPromotion,
+	}
+	capturePromotionTypes = [4]move.Type{
+		move.QueenPromotionCapture,
+		move.RookPromotionCapture,
+		move.BishopPromotionCapture,
+		move.KnightPromotionCapture,
+	}
+)
 
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
+// GenerateMoves adds all pseudo-legal moves for the current position to the
+// list: pawn pushes, captures, en passant, promotions, castling and the moves
+// of the other pieces. Moves leaving the king in check are included, so the
+// legality has to be checked with MakeMove.
+func (b *Board) GenerateMoves(list *move.List) {
+	b.generate(list, genAll, allSquares, allSquares)
+}
 
-						if !b.Occupancies[color.BLACK].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		} else {
-			if piece == BQ {
-				for bitboard != 0 {
+// GenerateCaptures adds the pseudo-legal captures to the list, including en
+// passant and promotions that capture.
+func (b *Board) GenerateCaptures(list *move.List) {
+	b.generate(list, genCaptures, allSquares, allSquares)
+}
 
-					sourceSq = bitboard.FirstOne()
+// GenerateQuiets adds the pseudo-legal moves that don't capture to the list,
+// including quiet promotions and castling.
+func (b *Board) GenerateQuiets(list *move.List) {
+	b.generate(list, genQuiets, allSquares, allSquares)
+}
 
-					// init piece attacks
-					attcks = attacks.GetQueenAttacks(sourceSq, b.Occupancies[color.BOTH]) & (^b.Occupancies[color.BLACK])
+// LookupMove returns the pseudo-legal move of the current position with the
+// same source square, target square and move type as m. The moving and captured
+// pieces of m are ignored, which allows checking moves kept without them, like
+// the moves in the transposition table.
+func (b *Board) LookupMove(m move.Move) (move.Move, bool) {
+	from := m.GetSourceSquare()
+	if !b.Occupancies[b.SideToMove].Test(from) {
+		return move.NoMove, false
+	}
 
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
+	var list move.List
+	b.generate(&list, genAll, bitboard.Bitboard(1)<<from, allSquares)
 
-						if !b.Occupancies[color.WHITE].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
+	const key = move.SourceMask | move.TargetMask | move.MoveTypeMask
+	for _, mv := range list.Moves() {
+		if mv&key == m&key {
+			return mv, true
 		}
+	}
+	return move.NoMove, false
+}
 
-		// generate king moves
-		if b.SideToMove == color.WHITE {
-			if piece == WK {
-				for bitboard != 0 {
-
-					sourceSq = bitboard.FirstOne()
-
-					// init piece attacks
-					attcks = attacks.KingAttacks[sourceSq] & (^b.Occupancies[color.WHITE])
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
+// generate adds the moves of the given kind for the pieces on the squares of
+// from to the list. Only moves landing on the squares of to are generated,
+// except that castling needs to include all squares and en passant is kept
+// when to holds the pawn it captures.
+func (b *Board) generate(list *move.List, kind genKind, from, to bitboard.Bitboard) {
+	side := b.SideToMove
+	occupied := b.Occupancies[color.BOTH]
+	enemies := b.Occupancies[side.Opp()]
+
+	var targets bitboard.Bitboard
+	if kind&genCaptures != 0 {
+		targets |= enemies
+	}
+	if kind&genQuiets != 0 {
+		targets |= ^occupied
+	}
+	targets &= to
 
-						if !b.Occupancies[color.BLACK].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
-				}
-			}
-		} else {
-			if piece == BK {
-				for bitboard != 0 {
+	base := WP
+	if side == color.BLACK {
+		base = BP
+	}
 
-					sourceSq = bitboard.FirstOne()
+	b.generatePawnMoves(list, kind, b.Bitboards[base+Pawn]&from, to)
 
-					// init piece attacks
-					attcks = attacks.KingAttacks[sourceSq] & (^b.Occupancies[color.BLACK])
+	for pieceType := Knight; pieceType <= King; pieceType++ {
+		piece := base + pieceType
+		pieces := b.Bitboards[piece] & from
 
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
+		for pieces != 0 {
+			sourceSq := pieces.FirstOne()
+			moves := pieceAttacks(pieceType, sourceSq, occupied) & targets
 
-						if !b.Occupancies[color.WHITE].Test(targetSq) {
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0),
-							)
-						} else {
-							captured := b.GetPieceAt(targetSq)
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-					}
+			for moves != 0 {
+				targetSq := moves.FirstOne()
+				if enemies.Test(targetSq) {
+					list.Add(move.EncodeMove(sourceSq, targetSq, piece, move.Capture, b.GetPieceAt(targetSq)))
+				} else {
+					list.Add(move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0))
 				}
 			}
 		}
 	}
 
-	return result
+	if kind&genQuiets != 0 && b.Bitboards[base+King]&from != 0 && to == allSquares {
+		b.generateCastlings(list, side)
+	}
 }
 
-// GenerateCaptures generates all capturing moves for the current position,
-// including pawn promotions which are considered tactical moves.
-func (b *Board) GenerateCaptures() []move.Move {
-	var result []move.Move
-	sourceSq, targetSq := 0, 0
-	bitboard, attcks := bitboard.Bitboard(0), bitboard.Bitboard(0)
-
-	for piece := WP; piece <= BK; piece++ {
-		bitboard = b.Bitboards[piece]
-
-		// Generate pawn captures
-		if b.SideToMove == color.WHITE {
-			if piece == WP {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-
-					// Pawn captures including promotions
-					attcks = attacks.PawnAttacks[color.WHITE][sourceSq] & b.Occupancies[color.BLACK]
-
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-						captured := b.GetPieceAt(targetSq)
-						// Handle promotion captures
-						if sourceSq >= A7 && sourceSq <= H7 {
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.QueenPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.RookPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.BishopPromotionCapture,
-									captured,
-								),
-							)
-							result = append(
-								result,
-								move.EncodeMove(
-									sourceSq,
-									targetSq,
-									piece,
-									move.KnightPromotionCapture,
-									captured,
-								),
-							)
-						} else {
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-						attcks &= attcks - 1 // clear LSB
-					}
-
-					// En passant captures
-					if b.EnPassant != -1 {
-						enpassantAttacks := attacks.PawnAttacks[color.WHITE][sourceSq] & (1 << b.EnPassant)
-						if enpassantAttacks != 0 {
-							targetSq := enpassantAttacks.FirstOne()
-							result = append(
-								result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.EnPassant, BP),
-							)
-						}
-					}
-					bitboard &= bitboard - 1 // clear LSB
-				}
-			}
-		} else { // Black pawns
-			if piece == BP {
-				for bitboard != 0 {
-					sourceSq = bitboard.FirstOne()
-
-					// Pawn captures including promotions
-					attcks = attacks.PawnAttacks[color.BLACK][sourceSq] & b.Occupancies[color.WHITE]
+// generatePawnMoves adds the moves of the given kind for the pawns of the side
+// to move: single and double pushes, captures, en passant and promotions. The
+// target squares are limited to to, as in generate.
+func (b *Board) generatePawnMoves(list *move.List, kind genKind, pawns, to bitboard.Bitboard) {
+	side := b.SideToMove
+	occupied := b.Occupancies[color.BOTH]
+	enemies := b.Occupancies[side.Opp()]
+
+	piece, enemyPawn, push := WP, BP, -8
+	promotionRank, startRank := A7, A2
+	if side == color.BLACK {
+		piece, enemyPawn, push = BP, WP, 8
+		promotionRank, startRank = A2, A7
+	}
 
-					for attcks != 0 {
-						targetSq = attcks.FirstOne()
-						captured := b.GetPieceAt(targetSq)
-						// Handle promotion captures
-						if sourceSq >= A2 && sourceSq <= H2 {
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.QueenPromotionCapture, captured),
-							)
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.RookPromotionCapture, captured),
-							)
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.BishopPromotionCapture, captured),
-							)
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.KnightPromotionCapture, captured),
-							)
-						} else {
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-							)
-						}
-						attcks &= attcks - 1 // clear LSB
-					}
+	for pawns != 0 {
+		sourceSq := pawns.FirstOne()
+		promotes := sourceSq >= promotionRank && sourceSq < promotionRank+8
 
-					// En passant captures
-					if b.EnPassant != -1 {
-						enpassantAttacks := attacks.PawnAttacks[color.BLACK][sourceSq] & (1 << b.EnPassant)
-						if enpassantAttacks != 0 {
-							targetSq := enpassantAttacks.FirstOne()
-							result = append(result,
-								move.EncodeMove(sourceSq, targetSq, piece, move.EnPassant, WP),
-							)
-						}
+		targetSq := sourceSq + push
+		if kind&genQuiets != 0 && !occupied.Test(targetSq) {
+			if to.Test(targetSq) {
+				if promotes {
+					for _, t := range promotionTypes {
+						list.Add(move.EncodeMove(sourceSq, targetSq, piece, t, 0))
 					}
-					bitboard &= bitboard - 1 // clear LSB
-				}
-			}
-		}
-
-		// Generate knight captures
-		if (b.SideToMove == color.WHITE && piece == WN) ||
-			(b.SideToMove == color.BLACK && piece == BN) {
-			for bitboard != 0 {
-				sourceSq = bitboard.FirstOne()
-				// Get attacks that hit enemy pieces
-				if b.SideToMove == color.WHITE {
-					attcks = attacks.KnightAttacks[sourceSq] & b.Occupancies[color.BLACK]
 				} else {
-					attcks = attacks.KnightAttacks[sourceSq] & b.Occupancies[color.WHITE]
-				}
-
-				// Generate captures
-				for attcks != 0 {
-					targetSq = attcks.FirstOne()
-					captured := b.GetPieceAt(targetSq)
-					result = append(
-						result,
-						move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-					)
-					attcks &= attcks - 1
+					list.Add(move.EncodeMove(sourceSq, targetSq, piece, move.Quiet, 0))
 				}
-				bitboard &= bitboard - 1
 			}
-		}
 
-		// Generate bishop captures
-		if (b.SideToMove == color.WHITE && piece == WB) ||
-			(b.SideToMove == color.BLACK && piece == BB) {
-			for bitboard != 0 {
-				sourceSq = bitboard.FirstOne()
-				if b.SideToMove == color.WHITE {
-					attcks = attacks.GetBishopAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & b.Occupancies[color.BLACK]
-				} else {
-					attcks = attacks.GetBishopAttacks(sourceSq, b.Occupancies[color.BOTH]) & b.Occupancies[color.WHITE]
-				}
-
-				for attcks != 0 {
-					targetSq = attcks.FirstOne()
-					captured := b.GetPieceAt(targetSq)
-					result = append(
-						result,
-						move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-					)
-					attcks &= attcks - 1
-				}
-				bitboard &= bitboard - 1
+			doubleSq := targetSq + push
+			if sourceSq >= startRank && sourceSq < startRank+8 &&
+				!occupied.Test(doubleSq) && to.Test(doubleSq) {
+				list.Add(move.EncodeMove(sourceSq, doubleSq, piece, move.DoublePawnPush, 0))
 			}
 		}
 
-		// Generate rook captures
-		if (b.SideToMove == color.WHITE && piece == WR) ||
-			(b.SideToMove == color.BLACK && piece == BR) {
-			for bitboard != 0 {
-				sourceSq = bitboard.FirstOne()
-				if b.SideToMove == color.WHITE {
-					attcks = attacks.GetRookAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & b.Occupancies[color.BLACK]
-				} else {
-					attcks = attacks.GetRookAttacks(sourceSq, b.Occupancies[color.BOTH]) & b.Occupancies[color.WHITE]
-				}
-
-				for attcks != 0 {
-					targetSq = attcks.FirstOne()
-					captured := b.GetPieceAt(targetSq)
-					result = append(
-						result,
-						move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-					)
-					attcks &= attcks - 1
-				}
-				bitboard &= bitboard - 1
-			}
+		if kind&genCaptures == 0 {
+			continue
 		}
 
-		// Generate queen captures
-		if (b.SideToMove == color.WHITE && piece == WQ) ||
-			(b.SideToMove == color.BLACK && piece == BQ) {
-			for bitboard != 0 {
-				sourceSq = bitboard.FirstOne()
-				if b.SideToMove == color.WHITE {
-					attcks = attacks.GetQueenAttacks(
-						sourceSq,
-						b.Occupancies[color.BOTH],
-					) & b.Occupancies[color.BLACK]
-				} else {
-					attcks = attacks.GetQueenAttacks(sourceSq, b.Occupancies[color.BOTH]) & b.Occupancies[color.WHITE]
-				}
+		captures := attacks.PawnAttacks[side][sourceSq] & enemies & to
+		for captures != 0 {
+			targetSq := captures.FirstOne()
+			captured := b.GetPieceAt(targetSq)
 
-				for attcks != 0 {
-					targetSq = attcks.FirstOne()
-					captured := b.GetPieceAt(targetSq)
-					result = append(
-						result,
-						move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-					)
-					attcks &= attcks - 1
+			if promotes {
+				for _, t := range capturePromotionTypes {
+					list.Add(move.EncodeMove(sourceSq, targetSq, piece, t, captured))
 				}
-				bitboard &= bitboard - 1
+			} else {
+				list.Add(move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured))
 			}
 		}
 
-		// Generate king captures
-		if (b.SideToMove == color.WHITE && piece == WK) ||
-			(b.SideToMove == color.BLACK && piece == BK) {
-			for bitboard != 0 {
-				sourceSq = bitboard.FirstOne()
-				if b.SideToMove == color.WHITE {
-					attcks = attacks.KingAttacks[sourceSq] & b.Occupancies[color.BLACK]
-				} else {
-					attcks = attacks.KingAttacks[sourceSq] & b.Occupancies[color.WHITE]
-				}
-
-				for attcks != 0 {
-					targetSq = attcks.FirstOne()
-					captured := b.GetPieceAt(targetSq)
-					result = append(
-						result,
-						move.EncodeMove(sourceSq, targetSq, piece, move.Capture, captured),
-					)
-					attcks &= attcks - 1
-				}
-				bitboard &= bitboard - 1
-			}
+		if b.EnPassant != -1 && attacks.PawnAttacks[side][sourceSq].Test(b.EnPassant) &&
+			(to.Test(b.EnPassant) || to.Test(b.EnPassant-push)) {
+			list.Add(move.EncodeMove(sourceSq, b.EnPassant, piece, move.EnPassant, enemyPawn))
 		}
 	}
+}
 
-	return result
+// pieceAttacks returns the squares attacked by a knight, bishop, rook, queen or
+// king on sq with the given occupancy.
+func pieceAttacks(pieceType, sq int, occupied bitboard.Bitboard) bitboard.Bitboard {
+	switch pieceType {
+	case Knight:
+		return attacks.KnightAttacks[sq]
+	case Bishop:
+		return attacks.GetBishopAttacks(sq, occupied)
+	case Rook:
+		return attacks.GetRookAttacks(sq, occupied)
+	case Queen:
+		return attacks.GetQueenAttacks(sq, occupied)
+	case King:
+		return attacks.KingAttacks[sq]
+	}
+	return 0
 }
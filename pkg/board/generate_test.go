@@ -0,0 +1,160 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+var generateFENs = []string{
+	"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+	"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1",
+}
+
+// noHistory scores every quiet move the same.
+type noHistory struct{}
+
+func (noHistory) Score(move.Move) int { return 0 }
+
+func TestGenerateCapturesAndQuiets(t *testing.T) {
+	for _, fen := range generateFENs {
+		b, err := ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("Failed to parse FEN: %v", err)
+		}
+
+		var all, captures, quiets move.List
+		b.GenerateMoves(&all)
+		b.GenerateCaptures(&captures)
+		b.GenerateQuiets(&quiets)
+
+		if captures.Len()+quiets.Len() != all.Len() {
+			t.Errorf("%s: %d captures and %d quiets, want %d moves",
+				fen, captures.Len(), quiets.Len(), all.Len())
+		}
+		for _, mv := range captures.Moves() {
+			if !mv.IsCapture() || !all.Contains(mv) {
+				t.Errorf("%s: unexpected capture %s", fen, mv)
+			}
+		}
+		for _, mv := range quiets.Moves() {
+			if mv.IsCapture() || !all.Contains(mv) {
+				t.Errorf("%s: unexpected quiet move %s", fen, mv)
+			}
+		}
+	}
+}
+
+func TestLookupMove(t *testing.T) {
+	b, err := ParseFEN(generateFENs[1])
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	var all move.List
+	b.GenerateMoves(&all)
+
+	// Moves kept with only squares and type set are found again
+	const key = move.SourceMask | move.TargetMask | move.MoveTypeMask
+	for _, mv := range all.Moves() {
+		if found, ok := b.LookupMove(mv & key); !ok || found != mv {
+			t.Errorf("LookupMove(%s) = %s, %v", mv, found, ok)
+		}
+	}
+
+	// A move of the opponent isn't
+	mv := move.EncodeMove(12, 28, 6, move.Quiet, 0) // e7e5 for black
+	if _, ok := b.LookupMove(mv); ok {
+		t.Errorf("LookupMove(%s) found a move of the wrong side", mv)
+	}
+}
+
+func TestMovePicker(t *testing.T) {
+	b, err := ParseFEN(generateFENs[1])
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	var all move.List
+	b.GenerateMoves(&all)
+
+	ttMove := findMove(t, &b, "e2a6")
+	killer := findMove(t, &b, "a2a3")
+	badCapture := findMove(t, &b, "f3f6")
+
+	var mp move.MovePicker
+	mp.Init(&b, noHistory{}, ttMove&(move.SourceMask|move.TargetMask|move.MoveTypeMask),
+		[2]move.Move{killer, move.NoMove}, move.NoMove)
+
+	var picked move.List
+	for mv := mp.Next(); mv != move.NoMove; mv = mp.Next() {
+		if picked.Contains(mv) {
+			t.Fatalf("Move %s returned twice", mv)
+		}
+		picked.Add(mv)
+	}
+
+	if picked.Len() != all.Len() {
+		t.Fatalf("Picked %d moves, want %d", picked.Len(), all.Len())
+	}
+	if picked.Get(0) != ttMove {
+		t.Errorf("First move is %s, want the TT move %s", picked.Get(0), ttMove)
+	}
+
+	// The good captures come right after the TT move, then the killer
+	i := 1
+	for ; picked.Get(i).IsCapture(); i++ {
+		if !b.SEEGreaterEqual(picked.Get(i), 0) {
+			t.Errorf("Losing capture %s picked before the quiet moves", picked.Get(i))
+		}
+	}
+	if picked.Get(i) != killer {
+		t.Errorf("First quiet move is %s, want the killer %s", picked.Get(i), killer)
+	}
+
+	// The captures losing material come last
+	for ; !picked.Get(i).IsCapture(); i++ {
+	}
+	for ; i < picked.Len(); i++ {
+		if mv := picked.Get(i); !mv.IsCapture() || b.SEEGreaterEqual(mv, 0) {
+			t.Errorf("Move %s picked among the losing captures", mv)
+		}
+	}
+	if !picked.Contains(badCapture) {
+		t.Errorf("Losing capture %s not picked", badCapture)
+	}
+}
+
+func TestMovePickerQuiescence(t *testing.T) {
+	b, err := ParseFEN(generateFENs[1])
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	var mp move.MovePicker
+	mp.InitQuiescence(&b)
+
+	prevScore := 1 << 30
+	count := 0
+	for mv := mp.Next(); mv != move.NoMove; mv = mp.Next() {
+		if !mv.IsCapture() || !b.SEEGreaterEqual(mv, 0) {
+			t.Errorf("Unexpected move %s in quiescence", mv)
+		}
+		if score := SEEValues[mv.GetCapturedPieceType()]; score > prevScore {
+			t.Errorf("Capture %s picked after a smaller victim", mv)
+		} else {
+			prevScore = score
+		}
+		count++
+	}
+
+	if count == 0 {
+		t.Error("No captures picked")
+	}
+}
@@ -4,10 +4,6 @@
 package board
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
-
 	"github.com/Tecu23/argov2/internal/hash"
 	"github.com/Tecu23/argov2/pkg/bitboard"
 	"github.com/Tecu23/argov2/pkg/color"
@@ -15,95 +11,6 @@ import (
 	"github.com/Tecu23/argov2/pkg/util"
 )
 
-// ParseFEN sets the board state according to a given FEN string.
-// It places pieces, sets side to move, castling rights, and en passant square.
-// rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -
-func ParseFEN(FEN string) (Board, error) {
-	b := Board{}
-	b.Reset()
-
-	fenIdx := 0
-	sq := 0
-
-	// Parse the ranks from top (rank=7) to bottom (rank=0)
-	for row := 0; row < 8; row++ {
-		for sq = row * 8; sq < row*8+8; {
-			char := string(FEN[fenIdx])
-			fenIdx++
-
-			if char == "/" {
-				continue
-			}
-
-			// If char is a digit, skip that many squares
-			if i, err := strconv.Atoi(char); err == nil {
-				for j := 0; j < i; j++ {
-					b.SetSq(Empty, sq)
-					sq++
-				}
-				continue
-			}
-
-			// Otherwise, it should be a piece character
-			if !strings.Contains(util.PcFen, char) {
-				return Board{}, fmt.Errorf(
-					"parse fen failed: %s",
-					fmt.Sprintf("Invalid piece %s try next one", char),
-				)
-			}
-
-			b.SetSq(util.Fen2pc(char), sq)
-			sq++
-		}
-	}
-
-	remaining := strings.Split(strings.TrimSpace(FEN[fenIdx:]), " ")
-
-	// Set side to move
-	if len(remaining) > 0 {
-		if remaining[0] == "w" {
-			b.SideToMove = color.WHITE
-		} else if remaining[0] == "b" {
-			b.SideToMove = color.BLACK
-		} else {
-			b.SideToMove = color.WHITE
-			return Board{}, fmt.Errorf(
-				"parse fen failed: %s",
-				fmt.Sprintf("%s invalid side to move color", remaining[0]),
-			)
-		}
-	}
-
-	// Set castling rights
-	b.Castlings = 0
-	if len(remaining) > 1 {
-		b.Castlings = ParseCastlings(remaining[1])
-	}
-
-	// Set en passant square
-	b.EnPassant = -1
-	if len(remaining) > 2 {
-		if remaining[2] != "-" {
-			b.EnPassant = util.Fen2Sq[remaining[2]]
-		}
-	}
-
-	// Set halfmove clock (for 50-move rule)
-	b.HalfMoveClock = 0
-	if len(remaining) > 3 {
-		cnt, err := strconv.Atoi(remaining[3])
-		if err != nil {
-			b.HalfMoveClock = 0
-		}
-
-		b.HalfMoveClock = uint8(cnt)
-	}
-
-	b.calculateHash()
-
-	return b, nil
-}
-
 // Mirror returns a new board that's flipped vertically (white pieces become black and vice versa)
 func (b *Board) Mirror() *Board {
 	// Create a new board
@@ -150,6 +57,11 @@ func (b *Board) Mirror() *Board {
 	}
 
 	mirrored.Castlings = newCastling
+	mirrored.Chess960 = b.Chess960
+	for idx, rookSq := range b.castlingRooks {
+		// White rights (0, 1) swap with black rights (2, 3)
+		mirrored.castlingRooks[idx^2] = rookSq ^ 56
+	}
 
 	// Mirror en passant square if exists
 	if b.EnPassant != -1 {
@@ -207,6 +119,14 @@ func (b *Board) GetPieceCountForSide(piece int, clr color.Color) int {
 	return 0
 }
 
+// HasNonPawnMaterial reports whether the side has any piece besides its king and pawns.
+func (b *Board) HasNonPawnMaterial(side color.Color) bool {
+	if side == color.WHITE {
+		return b.Bitboards[WN]|b.Bitboards[WB]|b.Bitboards[WR]|b.Bitboards[WQ] != 0
+	}
+	return b.Bitboards[BN]|b.Bitboards[BB]|b.Bitboards[BR]|b.Bitboards[BQ] != 0
+}
+
 func (b *Board) PieceCount(side color.Color) int {
 	if side == color.WHITE {
 		return b.Occupancies[color.WHITE].Count()
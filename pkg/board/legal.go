@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/bitboard"
+	"github.com/Tecu23/argov2/pkg/color"
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// GenerateLegalMoves adds the legal moves for the current position to the
+// list. Unlike GenerateMoves it never includes a move leaving the own king in
+// check, so the number of moves is the number of legal moves. Positions in
+// check are handed to GenerateEvasions.
+func (b *Board) GenerateLegalMoves(list *move.List) {
+	kingSq := b.kingSquare(b.SideToMove)
+	if kingSq == 64 {
+		// Without a king every move is legal
+		b.GenerateMoves(list)
+		return
+	}
+
+	if b.Checkers() != 0 {
+		b.GenerateEvasions(list)
+		return
+	}
+
+	var moves move.List
+	b.GenerateMoves(&moves)
+	b.addLegalMoves(list, &moves, kingSq)
+}
+
+// GenerateEvasions adds the legal moves of a position in check to the list:
+// king moves to safe squares and, against a single checker, captures of the
+// checker and moves blocking the check. Against a double check only the king
+// can move.
+func (b *Board) GenerateEvasions(list *move.List) {
+	kingSq := b.kingSquare(b.SideToMove)
+	if kingSq == 64 {
+		return
+	}
+
+	king := bitboard.Bitboard(1) << kingSq
+	checkers := b.Checkers()
+
+	var moves move.List
+	b.generate(&moves, genAll, king, ^b.Occupancies[b.SideToMove])
+
+	if checkers.Count() == 1 {
+		checkerSq := checkers.FirstOne()
+		b.generate(&moves, genAll, ^king, attacks.Between[kingSq][checkerSq]|bitboard.Bitboard(1)<<checkerSq)
+	}
+
+	b.addLegalMoves(list, &moves, kingSq)
+}
+
+// Checkers returns the enemy pieces giving check to the king of the side to move.
+func (b *Board) Checkers() bitboard.Bitboard {
+	kingSq := b.kingSquare(b.SideToMove)
+	if kingSq == 64 {
+		return 0
+	}
+	return b.attackersTo(kingSq, b.Occupancies[color.BOTH]) & b.Occupancies[b.SideToMove.Opp()]
+}
+
+// Pinned returns the pieces of the side to move that stand alone between their
+// king and an enemy slider. A pinned piece may only move along the line
+// through the king and the slider.
+func (b *Board) Pinned() bitboard.Bitboard {
+	kingSq := b.kingSquare(b.SideToMove)
+	if kingSq == 64 {
+		return 0
+	}
+	return b.pinned(kingSq)
+}
+
+func (b *Board) pinned(kingSq int) bitboard.Bitboard {
+	side := b.SideToMove
+	own := b.Occupancies[side]
+	enemies := b.Occupancies[side.Opp()]
+
+	enemyBase := BP
+	if side == color.BLACK {
+		enemyBase = WP
+	}
+	bishops := b.Bitboards[enemyBase+Bishop] | b.Bitboards[enemyBase+Queen]
+	rooks := b.Bitboards[enemyBase+Rook] | b.Bitboards[enemyBase+Queen]
+
+	// Sliders that would attack the king if only enemy pieces blocked them
+	snipers := attacks.GetBishopAttacks(kingSq, enemies)&bishops |
+		attacks.GetRookAttacks(kingSq, enemies)&rooks
+
+	var pinned bitboard.Bitboard
+	for snipers != 0 {
+		sq := snipers.FirstOne()
+		blockers := attacks.Between[kingSq][sq] & b.Occupancies[color.BOTH]
+		if blockers.Count() == 1 && blockers&own != 0 {
+			pinned |= blockers
+		}
+	}
+	return pinned
+}
+
+// addLegalMoves adds the moves of candidates that don't leave the own king in
+// check to the list.
+func (b *Board) addLegalMoves(list, candidates *move.List, kingSq int) {
+	pinned := b.pinned(kingSq)
+	for _, mv := range candidates.Moves() {
+		if b.isLegal(mv, kingSq, pinned) {
+			list.Add(mv)
+		}
+	}
+}
+
+// isLegal reports whether a pseudo-legal move keeps the own king out of check.
+// A move that doesn't deal with an existing check is only caught for king moves
+// and en passant, so the moves of the other pieces have to come from
+// GenerateEvasions when in check.
+func (b *Board) isLegal(m move.Move, kingSq int, pinned bitboard.Bitboard) bool {
+	from, to := m.GetSourceSquare(), m.GetTargetSquare()
+	enemies := b.Occupancies[b.SideToMove.Opp()]
+	occupied := b.Occupancies[color.BOTH]
+
+	switch {
+	case m.IsCastle():
+		// The generator checks the squares the king crosses, but in Chess960
+		// the castling rook may have shielded the king's target square
+		kingFrom, kingTo, rookFrom, rookTo := b.CastlingSquares(m)
+		occupied.Clear(kingFrom)
+		occupied.Clear(rookFrom)
+		occupied.Set(rookTo)
+		occupied.Set(kingTo)
+		return b.attackersTo(kingTo, occupied)&enemies == 0
+
+	case from == kingSq:
+		// The king must not stay on a line it is checked along
+		occupied.Clear(from)
+		return b.attackersTo(to, occupied)&enemies == 0
+
+	case m.IsEnPassant():
+		// Two pawns leave the rank at once, which may uncover the king
+		captured := to ^ 8
+		occupied.Clear(from)
+		occupied.Clear(captured)
+		occupied.Set(to)
+		enemies.Clear(captured)
+		return b.attackersTo(kingSq, occupied)&enemies == 0
+
+	default:
+		return !pinned.Test(from) || attacks.Line[kingSq][from].Test(to)
+	}
+}
+
+// kingSquare returns the square of the king of the given side, or 64 when the
+// side has no king.
+func (b *Board) kingSquare(side color.Color) int {
+	king := b.Bitboards[WK]
+	if side == color.BLACK {
+		king = b.Bitboards[BK]
+	}
+	return king.FirstOne()
+}
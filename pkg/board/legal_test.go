@@ -0,0 +1,107 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// legalPerft counts the leaf nodes at the given depth, using the number of
+// legal moves at the last ply instead of making them.
+func legalPerft(t *testing.T, b *Board, depth int) int64 {
+	t.Helper()
+
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+
+	// Every legal move must be accepted by MakeMove and every move MakeMove
+	// accepts must be legal
+	var pseudo move.List
+	b.GenerateMoves(&pseudo)
+	legal := 0
+	for _, mv := range pseudo.Moves() {
+		copyB := b.CopyBoard()
+		if copyB.MakeMove(mv, AllMoves) {
+			legal++
+			if !moves.Contains(mv) {
+				t.Fatalf("%s: legal move %s not generated", b.FEN(), mv)
+			}
+		}
+	}
+	if legal != moves.Len() {
+		t.Fatalf("%s: generated %d legal moves, want %d", b.FEN(), moves.Len(), legal)
+	}
+
+	if depth == 1 {
+		return int64(moves.Len())
+	}
+
+	nodes := int64(0)
+	for _, mv := range moves.Moves() {
+		copyB := b.CopyBoard()
+		copyB.MakeMove(mv, AllMoves)
+		nodes += legalPerft(t, &copyB, depth-1)
+	}
+	return nodes
+}
+
+func TestGenerateLegalMoves(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		depth    int
+		expected int64
+	}{
+		{"Initial Position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 3, 8902},
+		{"Kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 3, 97862},
+		{"Position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 4, 43238},
+		{"Position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 3, 9467},
+		{"Position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 3, 62379},
+		{"En Passant Pinned Along Rank", "8/8/8/K2Pp2r/8/8/8/7k w - e6 0 1", 1, 6},
+		{"Double Check", "4k3/8/8/8/8/5n2/8/r3K3 w - - 0 1", 1, 2},
+		{"Chess960", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 3, 12189},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN: %v", err)
+			}
+
+			if got := legalPerft(t, &b, tt.depth); got != tt.expected {
+				t.Errorf("Perft(%d) = %d, want %d", tt.depth, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckersAndPinned(t *testing.T) {
+	b, err := ParseFEN("4k3/8/1b6/8/8/3P4/4K2r/8 w - - 0 1")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	// The rook on h2 checks the king, the bishop on b6 isn't aligned with it
+	checkers := b.Checkers()
+	if checkers.Count() != 1 || !checkers.Test(55) {
+		t.Errorf("Checkers = %v, want h2", checkers)
+	}
+	if pinned := b.Pinned(); pinned != 0 {
+		t.Errorf("Pinned = %v, want none", pinned)
+	}
+
+	b, err = ParseFEN("4k3/8/8/1b6/8/3N4/4K3/8 w - - 0 1")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+	if pinned := b.Pinned(); pinned.Count() != 1 || !pinned.Test(43) {
+		t.Errorf("Pinned = %v, want d3", pinned)
+	}
+	if checkers := b.Checkers(); checkers != 0 {
+		t.Errorf("Checkers = %v, want none", checkers)
+	}
+}
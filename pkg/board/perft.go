@@ -7,7 +7,10 @@ package board
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
+	"github.com/Tecu23/argov2/pkg/move"
 	"github.com/Tecu23/argov2/pkg/util"
 )
 
@@ -21,18 +24,17 @@ func PerftDriver(b *Board, depth int) int64 {
 	}
 
 	nds := int64(0)
-	mvlst := b.GenerateMoves()
-
-	for _, mv := range mvlst {
-		copyB := b.CopyBoard()
+	var moves move.List
+	b.GenerateMoves(&moves)
 
+	for _, mv := range moves.Moves() {
 		if !b.MakeMove(mv, AllMoves) {
 			continue
 		}
 
 		nds += PerftDriver(b, depth-1)
 
-		b.TakeBack(copyB)
+		b.UnmakeMove()
 	}
 	return nds
 }
@@ -41,21 +43,19 @@ func PerftDriver(b *Board, depth int) int64 {
 // and total nodes visited. Useful for debugging and verifying correctness of move generation.
 func PerftTest(b *Board, depth int) int64 {
 	totalMoves := int64(0)
-	whiteMoves := b.GenerateMoves()
+	var moves move.List
+	b.GenerateMoves(&moves)
 	fmt.Printf("\n  Performance test\n\n")
 	start := util.GetTimeInMiliseconds()
 
-	for _, m := range whiteMoves {
-		moveNodes := int64(0)
-		copyB := b.CopyBoard()
-
+	for _, m := range moves.Moves() {
 		if !b.MakeMove(m, AllMoves) {
 			continue
 		}
-		moveNodes = PerftDriver(b, depth-1)
+		moveNodes := PerftDriver(b, depth-1)
 
 		// take back move
-		b.TakeBack(copyB)
+		b.UnmakeMove()
 
 		fmt.Printf("%s: %d\n", m, moveNodes)
 		totalMoves += moveNodes
@@ -70,3 +70,135 @@ func PerftTest(b *Board, depth int) int64 {
 
 	return totalMoves
 }
+
+// PerftResult holds the number of leaf nodes below a single root move.
+type PerftResult struct {
+	Move  move.Move
+	Nodes int64
+}
+
+// Divide counts the leaf nodes at the given depth below every legal move of
+// the position, which makes it easy to find the move a broken move generator
+// miscounts. The root moves are shared among the given number of goroutines,
+// and when table isn't nil the node counts of subtrees are cached in it. The
+// results are returned in move generation order.
+func Divide(b *Board, depth, threads int, table *PerftTable) []PerftResult {
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+
+	results := make([]PerftResult, moves.Len())
+	next := make(chan int, moves.Len())
+	for i := range results {
+		results[i].Move = moves.Get(i)
+		next <- i
+	}
+	close(next)
+
+	var wg sync.WaitGroup
+	for range max(1, min(threads, len(results))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Every goroutine makes its moves on its own copy of the board
+			pos := b.CopyBoard()
+			for i := range next {
+				pos.MakeMove(results[i].Move, AllMoves)
+				results[i].Nodes = perft(&pos, depth-1, table)
+				pos.UnmakeMove()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Perft returns the number of leaf nodes at the given depth, searching the
+// root moves in parallel like Divide does.
+func Perft(b *Board, depth, threads int, table *PerftTable) int64 {
+	if depth == 0 {
+		return 1
+	}
+
+	nodes := int64(0)
+	for _, result := range Divide(b, depth, threads, table) {
+		nodes += result.Nodes
+	}
+	return nodes
+}
+
+// perft counts the leaf nodes with legal move generation, so the last ply only
+// needs the number of legal moves instead of making them.
+func perft(b *Board, depth int, table *PerftTable) int64 {
+	if depth <= 0 {
+		return 1
+	}
+
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	if depth == 1 {
+		return int64(moves.Len())
+	}
+
+	if nodes, ok := table.probe(b.Hash(), depth); ok {
+		return nodes
+	}
+
+	nodes := int64(0)
+	for _, mv := range moves.Moves() {
+		b.MakeMove(mv, AllMoves)
+		nodes += perft(b, depth-1, table)
+		b.UnmakeMove()
+	}
+
+	table.store(b.Hash(), depth, nodes)
+	return nodes
+}
+
+// PerftTable caches the node counts of perft subtrees by position hash and
+// depth. It is safe for concurrent use: every entry stores its key xor its
+// data, so an entry torn by two goroutines writing at once fails to verify
+// instead of returning a wrong count.
+type PerftTable struct {
+	entries []perftEntry
+	mask    uint64
+}
+
+type perftEntry struct {
+	check atomic.Uint64
+	data  atomic.Uint64 // node count in the upper 56 bits, depth in the lower 8
+}
+
+// NewPerftTable creates a table using about the given number of megabytes.
+func NewPerftTable(megabytes int) *PerftTable {
+	size := uint64(1)
+	for size*2*16 <= uint64(megabytes)<<20 {
+		size *= 2
+	}
+	return &PerftTable{entries: make([]perftEntry, size), mask: size - 1}
+}
+
+func (t *PerftTable) probe(key uint64, depth int) (int64, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	e := &t.entries[key&t.mask]
+	data := e.data.Load()
+	if e.check.Load()^data != key || int(data&0xff) != depth {
+		return 0, false
+	}
+	return int64(data >> 8), true
+}
+
+func (t *PerftTable) store(key uint64, depth int, nodes int64) {
+	if t == nil {
+		return
+	}
+
+	e := &t.entries[key&t.mask]
+	data := uint64(nodes)<<8 | uint64(depth)
+	e.data.Store(data)
+	e.check.Store(key ^ data)
+}
@@ -16,6 +16,7 @@ func init() {
 	attacks.InitPawnAttacks()
 	attacks.InitKnightAttacks()
 	attacks.InitKingAttacks()
+	attacks.InitRays()
 	attacks.InitSliderPiecesAttacks(constants.Bishop)
 	attacks.InitSliderPiecesAttacks(constants.Rook)
 
@@ -211,3 +212,66 @@ func BenchmarkPerft(b *testing.B) {
 		PerftDriver(&board, depth)
 	}
 }
+
+func TestDivide(t *testing.T) {
+	b, err := ParseFEN(constants.StartPosition)
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	fen := b.FEN()
+	results := Divide(&b, 3, 4, nil)
+	if len(results) != 20 {
+		t.Fatalf("Divide returned %d root moves, want 20", len(results))
+	}
+
+	total := int64(0)
+	for _, result := range results {
+		total += result.Nodes
+		if result.Move.String() == "e2e4" && result.Nodes != 600 {
+			t.Errorf("e2e4: %d nodes, want 600", result.Nodes)
+		}
+	}
+	if total != 8902 {
+		t.Errorf("Divide total = %d, want 8902", total)
+	}
+
+	// The board is left untouched by the goroutines
+	if b.FEN() != fen {
+		t.Errorf("Board changed to %s", b.FEN())
+	}
+}
+
+func TestPerftTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		depth    int
+		expected int64
+	}{
+		{"Kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 4, 4085603},
+		{"Position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 5, 674624},
+		{"Position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 4, 422333},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN: %v", err)
+			}
+
+			if got := Perft(&b, tt.depth, 1, nil); got != tt.expected {
+				t.Errorf("Perft(%d) = %d, want %d", tt.depth, got, tt.expected)
+			}
+
+			// A tiny table is shared by all goroutines to force collisions
+			table := NewPerftTable(1)
+			for range 2 {
+				if got := Perft(&b, tt.depth, 4, table); got != tt.expected {
+					t.Errorf("Perft(%d) with table = %d, want %d", tt.depth, got, tt.expected)
+				}
+			}
+		})
+	}
+}
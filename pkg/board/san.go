@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// sanPattern matches a non-castling SAN move: optional piece letter, optional
+// origin file and rank, optional capture marker, target square and optional promotion.
+var sanPattern = regexp.MustCompile(`^([NBRQK])?([a-h])?([1-8])?(x)?([a-h][1-8])(?:=?([NBRQ]))?$`)
+
+// sanPieceTypes maps SAN piece letters to piece types.
+var sanPieceTypes = map[string]int{
+	"":  Pawn,
+	"N": Knight,
+	"B": Bishop,
+	"R": Rook,
+	"Q": Queen,
+	"K": King,
+}
+
+// legalMoves returns all legal moves in the current position.
+func (b *Board) legalMoves() []move.Move {
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	return append([]move.Move(nil), moves.Moves()...)
+}
+
+// SAN returns the move in Standard Algebraic Notation for the current position.
+// Moves are disambiguated by file, rank or both when several pieces of the same
+// type can reach the target square, and a "+" or "#" suffix is added for checks
+// and checkmates. The move is expected to be legal in the current position.
+func (b *Board) SAN(m move.Move) string {
+	sb := &strings.Builder{}
+
+	from := m.GetSourceSquare()
+	to := m.GetTargetSquare()
+	pcType := m.GetMovingPieceType()
+
+	switch {
+	case m.IsCastle():
+		if m.IsQueenCastle() {
+			sb.WriteString("O-O-O")
+		} else {
+			sb.WriteString("O-O")
+		}
+	case pcType == Pawn:
+		// Pawn captures are identified by the originating file
+		if m.IsCapture() {
+			sb.WriteByte(util.FileIdentifier[from%8])
+			sb.WriteByte('x')
+		}
+		sb.WriteString(util.Sq2Fen[to])
+
+		if m.IsPromotion() {
+			sb.WriteByte('=')
+			sb.WriteByte(util.PieceFen[m.GetPromotionPieceType()])
+		}
+	default:
+		sb.WriteByte(util.PieceFen[pcType])
+		sb.WriteString(b.disambiguation(m))
+		if m.IsCapture() {
+			sb.WriteByte('x')
+		}
+		sb.WriteString(util.Sq2Fen[to])
+	}
+
+	// Add the check or checkmate suffix
+	copyB := b.CopyBoard()
+	if copyB.MakeMove(m, AllMoves) && copyB.InCheck() {
+		if len(copyB.legalMoves()) == 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('+')
+		}
+	}
+
+	return sb.String()
+}
+
+// disambiguation returns the origin file, rank or square needed to tell the
+// move apart from other legal moves of the same piece type to the same square.
+func (b *Board) disambiguation(m move.Move) string {
+	from := m.GetSourceSquare()
+	to := m.GetTargetSquare()
+
+	ambiguous, sameFile, sameRank := false, false, false
+	for _, other := range b.legalMoves() {
+		otherFrom := other.GetSourceSquare()
+		if otherFrom == from || other.GetTargetSquare() != to ||
+			other.GetMovingPiece() != m.GetMovingPiece() {
+			continue
+		}
+
+		ambiguous = true
+		if otherFrom%8 == from%8 {
+			sameFile = true
+		}
+		if otherFrom/8 == from/8 {
+			sameRank = true
+		}
+	}
+
+	square := util.Sq2Fen[from]
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return square[:1]
+	case !sameRank:
+		return square[1:]
+	default:
+		return square
+	}
+}
+
+// ParseSAN parses a move written in Standard Algebraic Notation and returns
+// the matching legal move in the current position. Castling may be written
+// with either letters (O-O) or zeros (0-0), and trailing check, mate and
+// annotation symbols such as "+", "#", "!" or "?" are ignored.
+func (b *Board) ParseSAN(san string) (move.Move, error) {
+	s := strings.TrimRight(strings.TrimSpace(san), "+#!?")
+	if s == "" {
+		return move.NoMove, fmt.Errorf("parse san failed: empty move")
+	}
+
+	legal := b.legalMoves()
+
+	// Castling moves
+	switch strings.ReplaceAll(s, "0", "O") {
+	case "O-O":
+		for _, mv := range legal {
+			if mv.IsCastle() && !mv.IsQueenCastle() {
+				return mv, nil
+			}
+		}
+		return move.NoMove, fmt.Errorf("parse san failed: %s castling is not legal", san)
+	case "O-O-O":
+		for _, mv := range legal {
+			if mv.IsQueenCastle() {
+				return mv, nil
+			}
+		}
+		return move.NoMove, fmt.Errorf("parse san failed: %s castling is not legal", san)
+	}
+
+	parts := sanPattern.FindStringSubmatch(s)
+	if parts == nil {
+		return move.NoMove, fmt.Errorf("parse san failed: %s is not a valid move", san)
+	}
+
+	pcType := sanPieceTypes[parts[1]]
+	fromFile, fromRank := parts[2], parts[3]
+	isCapture := parts[4] != ""
+	to := util.Fen2Sq[parts[5]]
+	promotion := parts[6]
+
+	if promotion != "" && pcType != Pawn {
+		return move.NoMove, fmt.Errorf("parse san failed: %s only pawns can promote", san)
+	}
+
+	found := move.NoMove
+	for _, mv := range legal {
+		if mv.GetTargetSquare() != to || mv.GetMovingPieceType() != pcType || mv.IsCastle() {
+			continue
+		}
+
+		origin := util.Sq2Fen[mv.GetSourceSquare()]
+		if fromFile != "" && origin[:1] != fromFile {
+			continue
+		}
+		if fromRank != "" && origin[1:] != fromRank {
+			continue
+		}
+		if isCapture && !mv.IsCapture() {
+			continue
+		}
+
+		if mv.IsPromotion() {
+			if promotion == "" ||
+				util.PieceFen[mv.GetPromotionPieceType()] != promotion[0] {
+				continue
+			}
+		} else if promotion != "" {
+			continue
+		}
+
+		if found != move.NoMove {
+			return move.NoMove, fmt.Errorf("parse san failed: %s is ambiguous", san)
+		}
+		found = mv
+	}
+
+	if found == move.NoMove {
+		return move.NoMove, fmt.Errorf("parse san failed: %s is not a legal move", san)
+	}
+
+	return found, nil
+}
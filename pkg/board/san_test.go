@@ -0,0 +1,140 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+func TestSAN(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		move     string
+		expected string
+	}{
+		{"Pawn Push", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e2e4", "e4"},
+		{"Knight Move", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "g1f3", "Nf3"},
+		{"Pawn Capture", "rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 2", "e4d5", "exd5"},
+		{"En Passant", "rnbqkbnr/ppp2ppp/4p3/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3", "e5d6", "exd6"},
+		{"Disambiguate By File", "4k3/8/8/8/8/8/8/1N2KN2 w - - 0 1", "b1d2", "Nbd2"},
+		{"Disambiguate By File Other Knight", "4k3/8/8/8/8/8/8/1N2KN2 w - - 0 1", "f1d2", "Nfd2"},
+		{"Disambiguate By Rank", "4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a1a3", "R1a3"},
+		{"Disambiguate By Square", "7k/8/8/8/Q1Q5/8/Q7/4K3 w - - 0 1", "a4b3", "Qa4b3"},
+		{"Pinned Piece Needs No Disambiguation", "k3r3/8/8/8/8/8/4N3/1N2K3 w - - 0 1", "b1c3", "Nc3"},
+		{"Check", "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "a1a8", "Ra8+"},
+		{"Checkmate", "7k/8/6K1/8/8/8/8/R7 w - - 0 1", "a1a8", "Ra8#"},
+		{"King Side Castle", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"Queen Side Castle", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8c8", "O-O-O"},
+		{"Promotion", "8/4P3/8/8/8/8/k7/4K3 w - - 0 1", "e7e8q", "e8=Q"},
+		{"Under Promotion With Capture", "3r4/4P3/8/8/8/8/k7/4K3 w - - 0 1", "e7d8n", "exd8=N"},
+		{"Promotion With Check", "8/4P3/8/8/k7/8/8/4K3 w - - 0 1", "e7e8q", "e8=Q+"},
+		{"Rook Promotion Mate", "k7/4P3/1K6/8/8/8/8/8 w - - 0 1", "e7e8r", "e8=R#"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			mv := findMove(t, &b, tt.move)
+			if got := b.SAN(mv); got != tt.expected {
+				t.Errorf("SAN() = %s, want %s", got, tt.expected)
+			}
+
+			// Every generated SAN must parse back to the same move
+			parsed, err := b.ParseSAN(tt.expected)
+			if err != nil {
+				t.Fatalf("ParseSAN(%s) failed: %v", tt.expected, err)
+			}
+			if parsed != mv {
+				t.Errorf("ParseSAN(%s) = %s, want %s", tt.expected, parsed, mv)
+			}
+		})
+	}
+}
+
+func TestParseSAN(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		san      string
+		expected string
+	}{
+		{"Castling With Zeros", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "0-0", "e1g1"},
+		{"Long Castling With Zeros", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "0-0-0", "e1c1"},
+		{"Annotated Move", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e4!?", "e2e4"},
+		{"Redundant Check Suffix", "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "Ra8+", "a1a8"},
+		{"Promotion Without Equals", "8/4P3/8/8/8/8/k7/4K3 w - - 0 1", "e8N", "e7e8n"},
+		{"Over Disambiguated", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Ng1f3", "g1f3"},
+		{"Bishop Versus Pawn File", "4k3/8/8/8/8/1p6/P1B5/4K3 w - - 0 1", "axb3", "a2b3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			mv, err := b.ParseSAN(tt.san)
+			if err != nil {
+				t.Fatalf("ParseSAN(%s) failed: %v", tt.san, err)
+			}
+			if mv.String() != tt.expected {
+				t.Errorf("ParseSAN(%s) = %s, want %s", tt.san, mv, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSANErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		san  string
+	}{
+		{"Empty", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", ""},
+		{"Garbage", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Zz9"},
+		{"Illegal Move", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e5"},
+		{"Ambiguous Move", "4k3/8/8/8/8/8/8/1N2KN2 w - - 0 1", "Nd2"},
+		{"Castling Not Allowed", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "O-O"},
+		{"Missing Promotion", "8/4P3/8/8/8/8/k7/4K3 w - - 0 1", "e8"},
+		{"Piece Promotion", "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "Ra8=Q"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN %s: %v", tt.fen, err)
+			}
+
+			if mv, err := b.ParseSAN(tt.san); err == nil {
+				t.Errorf("Expected error for %q, got %s", tt.san, mv)
+			}
+		})
+	}
+}
+
+// findMove returns the generated move matching the given coordinate notation.
+func findMove(t *testing.T, b *Board, s string) move.Move {
+	t.Helper()
+
+	var moves move.List
+	b.GenerateMoves(&moves)
+
+	for _, mv := range moves.Moves() {
+		if mv.String() == s {
+			return mv
+		}
+	}
+
+	t.Fatalf("Move %s not found", s)
+	return move.NoMove
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/bitboard"
+	"github.com/Tecu23/argov2/pkg/color"
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// SEEValues holds the piece values used by the static exchange evaluation,
+// indexed by piece type. The king is never captured, so it's worth nothing.
+var SEEValues = [6]int{100, 300, 300, 500, 900, 0}
+
+// SEE returns the static exchange evaluation of a move: the material the side
+// to move wins or loses when both sides keep recapturing on the target square
+// with their least valuable attacker, and either side may stop when going on
+// would lose material. Sliders behind other attackers (x-rays) join in once
+// the pieces in front of them have captured. Pins are not taken into account.
+func (b *Board) SEE(m move.Move) int {
+	if m.IsCastle() {
+		return 0
+	}
+
+	from, to := m.GetSourceSquare(), m.GetTargetSquare()
+
+	var gain [32]int
+	gain[0] = b.captureValue(m)
+
+	// The value of the piece standing on the target square, about to be captured
+	onSquare := SEEValues[m.GetMovingPieceType()]
+	if m.IsPromotion() {
+		onSquare = SEEValues[m.GetPromotionPieceType()]
+	}
+
+	occupied := b.Occupancies[color.BOTH]
+	occupied.Clear(from)
+	if m.IsEnPassant() {
+		occupied.Clear(to ^ 8)
+	}
+	attackers := b.attackersTo(to, occupied)
+
+	side := b.SideToMove.Opp()
+	d := 0
+	for d < len(gain)-1 {
+		sq, pieceType := b.leastValuableAttacker(attackers&occupied, side)
+		if sq == -1 {
+			break
+		}
+
+		// The king may only capture when the square isn't defended anymore
+		if pieceType == King {
+			occupied.Clear(sq)
+			if attackers&occupied&b.Occupancies[side.Opp()] != 0 {
+				break
+			}
+		}
+
+		d++
+		gain[d] = onSquare - gain[d-1]
+
+		occupied.Clear(sq)
+		attackers |= b.xrayAttackers(to, occupied, pieceType)
+		onSquare = SEEValues[pieceType]
+		side = side.Opp()
+	}
+
+	// Each side either captures or stands pat, whatever is better for it
+	for ; d > 0; d-- {
+		gain[d-1] = -max(-gain[d-1], gain[d])
+	}
+	return gain[0]
+}
+
+// SEEGreaterEqual reports whether the static exchange evaluation of a move is
+// at least threshold. It's cheaper than SEE, since it stops as soon as the
+// outcome relative to the threshold is known.
+func (b *Board) SEEGreaterEqual(m move.Move, threshold int) bool {
+	if m.IsCastle() {
+		return threshold <= 0
+	}
+
+	from, to := m.GetSourceSquare(), m.GetTargetSquare()
+
+	// Even winning the captured piece for free isn't enough
+	swap := b.captureValue(m) - threshold
+	if swap < 0 {
+		return false
+	}
+
+	moved := SEEValues[m.GetMovingPieceType()]
+	if m.IsPromotion() {
+		moved = SEEValues[m.GetPromotionPieceType()]
+	}
+
+	// Even losing the moved piece for nothing is good enough
+	swap = moved - swap
+	if swap <= 0 {
+		return true
+	}
+
+	occupied := b.Occupancies[color.BOTH]
+	occupied.Clear(from)
+	occupied.Clear(to)
+	if m.IsEnPassant() {
+		occupied.Clear(to ^ 8)
+	}
+	attackers := b.attackersTo(to, occupied)
+
+	side := b.SideToMove
+	result := true
+	for {
+		side = side.Opp()
+		attackers &= occupied
+
+		sq, pieceType := b.leastValuableAttacker(attackers, side)
+		if sq == -1 {
+			break
+		}
+		result = !result
+
+		// Capturing with the king is only legal when nothing recaptures
+		if pieceType == King {
+			if attackers&b.Occupancies[side.Opp()] != 0 {
+				return !result
+			}
+			return result
+		}
+
+		swap = SEEValues[pieceType] - swap
+		if result && swap < 1 || !result && swap < 0 {
+			break
+		}
+
+		occupied.Clear(sq)
+		attackers |= b.xrayAttackers(to, occupied, pieceType)
+	}
+
+	return result
+}
+
+// captureValue returns the material a move wins right away, including the
+// gain of promoting the pawn.
+func (b *Board) captureValue(m move.Move) int {
+	value := 0
+	if m.IsCapture() {
+		value = SEEValues[m.GetCapturedPieceType()]
+	}
+	if m.IsPromotion() {
+		value += SEEValues[m.GetPromotionPieceType()] - SEEValues[Pawn]
+	}
+	return value
+}
+
+// attackersTo returns the pieces of both sides attacking sq with the given
+// occupancy. The result can hold pieces that aren't in occupied anymore.
+func (b *Board) attackersTo(sq int, occupied bitboard.Bitboard) bitboard.Bitboard {
+	bishops := b.Bitboards[WB] | b.Bitboards[BB] | b.Bitboards[WQ] | b.Bitboards[BQ]
+	rooks := b.Bitboards[WR] | b.Bitboards[BR] | b.Bitboards[WQ] | b.Bitboards[BQ]
+
+	return attacks.PawnAttacks[color.BLACK][sq]&b.Bitboards[WP] |
+		attacks.PawnAttacks[color.WHITE][sq]&b.Bitboards[BP] |
+		attacks.KnightAttacks[sq]&(b.Bitboards[WN]|b.Bitboards[BN]) |
+		attacks.KingAttacks[sq]&(b.Bitboards[WK]|b.Bitboards[BK]) |
+		attacks.GetBishopAttacks(sq, occupied)&bishops |
+		attacks.GetRookAttacks(sq, occupied)&rooks
+}
+
+// xrayAttackers returns the sliders attacking sq after a piece of the given
+// type left the line between them and the square.
+func (b *Board) xrayAttackers(sq int, occupied bitboard.Bitboard, pieceType int) bitboard.Bitboard {
+	var result bitboard.Bitboard
+
+	if pieceType == Pawn || pieceType == Bishop || pieceType == Queen {
+		bishops := b.Bitboards[WB] | b.Bitboards[BB] | b.Bitboards[WQ] | b.Bitboards[BQ]
+		result |= attacks.GetBishopAttacks(sq, occupied) & bishops
+	}
+	if pieceType == Rook || pieceType == Queen {
+		rooks := b.Bitboards[WR] | b.Bitboards[BR] | b.Bitboards[WQ] | b.Bitboards[BQ]
+		result |= attacks.GetRookAttacks(sq, occupied) & rooks
+	}
+
+	return result & occupied
+}
+
+// leastValuableAttacker returns the square and type of the cheapest piece of
+// the given side among attackers, or -1 when the side has no attacker.
+func (b *Board) leastValuableAttacker(attackers bitboard.Bitboard, side color.Color) (int, int) {
+	base := WP
+	if side == color.BLACK {
+		base = BP
+	}
+
+	for pieceType := Pawn; pieceType <= King; pieceType++ {
+		if bb := attackers & b.Bitboards[base+pieceType]; bb != 0 {
+			return bb.FirstOne(), pieceType
+		}
+	}
+	return -1, -1
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package board
+
+import (
+	"testing"
+)
+
+func TestSEE(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		move     string
+		expected int
+	}{
+		{"Free Pawn", "1k1r4/1pp4p/p7/4p3/8/P5P1/1PP4P/2K1R3 w - - 0 1", "e1e5", 100},
+		{"Defended Pawn With Knight", "1k1r3q/1ppn3p/p4b2/4p3/8/P2N2P1/1PP1R1BP/2K1Q3 w - - 0 1", "d3e5", -200},
+		{"Pawn Takes Defended Knight", "4k3/8/2p5/3n4/4P3/8/8/4K3 w - - 0 1", "e4d5", 200},
+		{"Queen Takes Defended Pawn", "4k3/8/2p5/3p4/8/8/3Q4/4K3 w - - 0 1", "d2d5", -800},
+		{"Rook Backed By Rook", "3rk3/3r4/8/3p4/8/8/3R4/3RK3 w - - 0 1", "d2d5", -400},
+		{"X-Ray Queen Behind Rook", "3rk3/8/8/3p4/8/8/3R4/3QK3 w - - 0 1", "d2d5", 100},
+		{"Equal Trade", "4k3/8/4p3/3n4/8/4N3/8/4K3 w - - 0 1", "e3d5", 0},
+		{"King Recaptures", "8/8/4k3/3p4/8/8/8/3RK3 w - - 0 1", "d1d5", -400},
+		{"King Cannot Recapture Defended", "8/8/4k3/3p4/8/1B6/8/3RK3 w - - 0 1", "d1d5", 100},
+		{"Quiet Move To Safe Square", "4k3/8/8/3p4/8/8/8/2N1K3 w - - 0 1", "c1b3", 0},
+		{"Quiet Move Hanging Piece", "4k3/8/8/8/3p4/8/8/1N2K3 w - - 0 1", "b1c3", -300},
+		{"En Passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", 100},
+		{"Promotion", "4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8q", 800},
+		{"Defended Promotion", "1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7a8q", -100},
+		{"Promotion Capture", "1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7b8q", 1300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("Failed to parse FEN: %v", err)
+			}
+
+			m := findMove(t, &b, tt.move)
+
+			if got := b.SEE(m); got != tt.expected {
+				t.Errorf("SEE(%s) = %d, want %d", tt.move, got, tt.expected)
+			}
+
+			// SEEGreaterEqual has to agree with SEE around the exact value
+			for _, threshold := range []int{tt.expected - 1, tt.expected, tt.expected + 1} {
+				if got := b.SEEGreaterEqual(m, threshold); got != (tt.expected >= threshold) {
+					t.Errorf("SEEGreaterEqual(%s, %d) = %v", tt.move, threshold, got)
+				}
+			}
+		})
+	}
+}
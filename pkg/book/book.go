@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+// Package book reads opening books in the Polyglot format. A book is a sorted
+// list of 16 byte entries, each holding the key of a position, a move and the
+// weight of the move; positions are found by binary search on the key.
+package book
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// entrySize is the size of an entry in a Polyglot book file
+const entrySize = 16
+
+// Entry is a single book entry. Move uses the Polyglot encoding: the target
+// file and rank in bits 0-5, the source file and rank in bits 6-11 and the
+// promotion piece (1 knight to 4 queen) in bits 12-14. Ranks and files count
+// from a1, and castling is written as the king capturing its own rook.
+type Entry struct {
+	Key    uint64
+	Move   uint16
+	Weight uint16
+	Learn  uint32
+}
+
+// Book is an opening book loaded into memory.
+type Book struct {
+	entries []Entry
+}
+
+// Open loads the book file at path.
+func Open(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bk, err := Load(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("load book %v failed: %w", path, err)
+	}
+	return bk, nil
+}
+
+// Load reads a book from r. Entries must be sorted by key as in every
+// Polyglot book, but the book is sorted again when they aren't.
+func Load(r io.Reader) (*Book, error) {
+	bk := &Book{}
+
+	var buf [entrySize]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			if err == io.ErrUnexpectedEOF {
+				return nil, errors.New("truncated entry")
+			}
+			return nil, err
+		}
+		bk.entries = append(bk.entries, Entry{
+			Key:    binary.BigEndian.Uint64(buf[0:8]),
+			Move:   binary.BigEndian.Uint16(buf[8:10]),
+			Weight: binary.BigEndian.Uint16(buf[10:12]),
+			Learn:  binary.BigEndian.Uint32(buf[12:16]),
+		})
+	}
+
+	cmp := func(a, b Entry) int {
+		switch {
+		case a.Key < b.Key:
+			return -1
+		case a.Key > b.Key:
+			return 1
+		}
+		return 0
+	}
+	if !slices.IsSortedFunc(bk.entries, cmp) {
+		slices.SortStableFunc(bk.entries, cmp)
+	}
+	return bk, nil
+}
+
+// Len returns the number of entries in the book.
+func (bk *Book) Len() int {
+	return len(bk.entries)
+}
+
+// Candidate is a book move of a position with its weight.
+type Candidate struct {
+	Move   move.Move
+	Weight int
+}
+
+// Probe returns the book moves of the position, in book order. Entries whose
+// move isn't legal in the position, such as the moves of another position with
+// the same key, are skipped.
+func (bk *Book) Probe(b *board.Board) []Candidate {
+	key := Key(b)
+	i, _ := slices.BinarySearchFunc(bk.entries, key, func(e Entry, key uint64) int {
+		switch {
+		case e.Key < key:
+			return -1
+		case e.Key > key:
+			return 1
+		}
+		return 0
+	})
+
+	if i == len(bk.entries) || bk.entries[i].Key != key {
+		return nil
+	}
+
+	var candidates []Candidate
+	var legal move.List
+	b.GenerateLegalMoves(&legal)
+	for ; i < len(bk.entries) && bk.entries[i].Key == key; i++ {
+		if mv, ok := decodeMove(b, legal.Moves(), bk.entries[i].Move); ok {
+			candidates = append(candidates, Candidate{Move: mv, Weight: int(bk.entries[i].Weight)})
+		}
+	}
+	return candidates
+}
+
+// Selection is the way a move is chosen from the book moves of a position.
+type Selection int
+
+const (
+	// WeightedRandom picks a move at random, with a probability in proportion
+	// to its weight.
+	WeightedRandom Selection = iota
+	// BestWeight always picks the move with the highest weight.
+	BestWeight
+)
+
+// Pick chooses a book move for the position. Moves with zero weight are
+// never picked. The random source r may be nil to use the default one.
+func (bk *Book) Pick(b *board.Board, selection Selection, r *rand.Rand) (move.Move, bool) {
+	candidates := bk.Probe(b)
+
+	total, best := 0, -1
+	for i, c := range candidates {
+		total += c.Weight
+		if c.Weight > 0 && (best == -1 || c.Weight > candidates[best].Weight) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return move.NoMove, false
+	}
+	if selection == BestWeight {
+		return candidates[best].Move, true
+	}
+
+	var n int
+	if r != nil {
+		n = r.Intn(total)
+	} else {
+		n = rand.Intn(total)
+	}
+	for _, c := range candidates {
+		if n < c.Weight {
+			return c.Move, true
+		}
+		n -= c.Weight
+	}
+	return candidates[best].Move, true
+}
+
+// decodeMove finds the legal move matching a Polyglot move
+func decodeMove(b *board.Board, legal []move.Move, pm uint16) (move.Move, bool) {
+	to := polyglotSquare(int(pm & 0x3f))
+	from := polyglotSquare(int(pm>>6) & 0x3f)
+	promotion := int(pm>>12) & 0x7
+
+	for _, mv := range legal {
+		if mv.IsCastle() {
+			kingFrom, _, rookFrom, _ := b.CastlingSquares(mv)
+			if from == kingFrom && to == rookFrom {
+				return mv, true
+			}
+			continue
+		}
+		if mv.GetSourceSquare() != from || mv.GetTargetSquare() != to {
+			continue
+		}
+		if mv.IsPromotion() != (promotion != 0) {
+			continue
+		}
+		if promotion != 0 && mv.GetPromotionPieceType() != promotion {
+			continue
+		}
+		return mv, true
+	}
+	return move.NoMove, false
+}
+
+// polyglotSquare converts a Polyglot square, counted from a1, to a board square
+func polyglotSquare(sq int) int {
+	return (7-sq/8)*8 + sq%8
+}
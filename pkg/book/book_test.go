@@ -0,0 +1,187 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package book
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+func mustParseFEN(t *testing.T, fen string) board.Board {
+	t.Helper()
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		t.Fatalf("ParseFEN(%q) failed: %v", fen, err)
+	}
+	return b
+}
+
+// TestKey checks the keys against the examples of the Polyglot specification
+func TestKey(t *testing.T) {
+	tests := []struct {
+		fen      string
+		expected uint64
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 0x463b96181691fc9c},
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", 0x823c9b50fd114196},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", 0x0756b94461c50fb0},
+		{"rnbqkbnr/ppp1pppp/8/3pP3/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2", 0x662fafb965db29d4},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", 0x22a48b5a8e47ff78},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR b kq - 0 3", 0x652a607ca3f242c1},
+		{"rnbq1bnr/ppp1pkpp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR w - - 0 4", 0x00fdd303c946bdd9},
+		{"rnbqkbnr/p1pppppp/8/8/PpP4P/8/1P1PPPP1/RNBQKBNR b KQkq c3 0 3", 0x3c8123ea7b067637},
+		{"rnbqkbnr/p1pppppp/8/8/P6P/R1p5/1P1PPPP1/1NBQKBNR b Kkq - 0 4", 0x5c3f9b829b279560},
+	}
+
+	for _, tt := range tests {
+		b := mustParseFEN(t, tt.fen)
+		if got := Key(&b); got != tt.expected {
+			t.Errorf("Key(%q) = %#016x, want %#016x", tt.fen, got, tt.expected)
+		}
+	}
+}
+
+// encodeMove writes a move in coordinate notation in the Polyglot encoding
+func encodeMove(s string) uint16 {
+	square := func(s string) uint16 {
+		return uint16(s[1]-'1')*8 + uint16(s[0]-'a')
+	}
+	pm := square(s[2:4]) | square(s[0:2])<<6
+	if len(s) == 5 {
+		pm |= uint16(1+bytes.IndexByte([]byte("nbrq"), s[4])) << 12
+	}
+	return pm
+}
+
+// bookData builds a book file from the moves and weights of some positions
+func bookData(t *testing.T, positions map[string]map[string]uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for fen, moves := range positions {
+		b := mustParseFEN(t, fen)
+		for mv, weight := range moves {
+			var entry [entrySize]byte
+			binary.BigEndian.PutUint64(entry[0:8], Key(&b))
+			binary.BigEndian.PutUint16(entry[8:10], encodeMove(mv))
+			binary.BigEndian.PutUint16(entry[10:12], weight)
+			buf.Write(entry[:])
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestProbe(t *testing.T) {
+	const (
+		startPos = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+		castling = "r3k2r/pppq1ppp/2npbn2/4p3/4P3/2NPBN2/PPPQ1PPP/R3K2R w KQkq - 4 8"
+		promote  = "8/2P1k3/8/8/8/8/4K3/8 w - - 0 1"
+	)
+	data := bookData(t, map[string]map[string]uint16{
+		startPos: {"e2e4": 10, "d2d4": 5, "e2e5": 7},
+		castling: {"e1h1": 3, "e1a1": 2},
+		promote:  {"c7c8q": 1, "c7c8n": 1},
+	})
+
+	bk, err := Load(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if bk.Len() != 7 {
+		t.Fatalf("Len = %d, want 7", bk.Len())
+	}
+
+	tests := []struct {
+		fen      string
+		expected []string
+	}{
+		{startPos, []string{"d2d4", "e2e4"}}, // e2e5 isn't legal
+		{castling, []string{"e1c1", "e1g1"}},
+		{promote, []string{"c7c8n", "c7c8q"}},
+		{"8/8/8/8/8/8/8/K6k w - - 0 1", nil},
+	}
+
+	for _, tt := range tests {
+		b := mustParseFEN(t, tt.fen)
+		var got []string
+		for _, c := range bk.Probe(&b) {
+			got = append(got, c.Move.String())
+		}
+		slices.Sort(got)
+		if len(got) != len(tt.expected) {
+			t.Errorf("Probe(%q) = %v, want %v", tt.fen, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("Probe(%q) = %v, want %v", tt.fen, got, tt.expected)
+				break
+			}
+		}
+	}
+
+	if _, err := Load(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Error("Load of a truncated book succeeded, want an error")
+	}
+}
+
+func TestPick(t *testing.T) {
+	const startPos = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	data := bookData(t, map[string]map[string]uint16{
+		startPos: {"e2e4": 3, "d2d4": 1, "g1f3": 0},
+	})
+	bk, err := Load(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	b := mustParseFEN(t, startPos)
+	if mv, ok := bk.Pick(&b, BestWeight, nil); !ok || mv.String() != "e2e4" {
+		t.Errorf("Pick(BestWeight) = %v, %v, want e2e4", mv, ok)
+	}
+
+	counts := make(map[move.Move]int)
+	r := rand.New(rand.NewSource(1))
+	for range 4000 {
+		mv, ok := bk.Pick(&b, WeightedRandom, r)
+		if !ok {
+			t.Fatal("Pick(WeightedRandom) found no move")
+		}
+		counts[mv]++
+	}
+	if len(counts) != 2 {
+		t.Fatalf("picked moves %v, want e2e4 and d2d4 only", counts)
+	}
+	for mv, n := range counts {
+		if mv.String() == "e2e4" && (n < 2800 || n > 3200) {
+			t.Errorf("picked e2e4 %d times out of 4000, want about 3000", n)
+		}
+	}
+
+	empty := mustParseFEN(t, "8/8/8/8/8/8/8/K6k w - - 0 1")
+	if _, ok := bk.Pick(&empty, WeightedRandom, r); ok {
+		t.Error("Pick found a move for a position outside of the book")
+	}
+}
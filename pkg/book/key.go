@@ -0,0 +1,56 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package book
+
+import (
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/color"
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+// Key returns the Polyglot key of the position. It differs from the board
+// hash, which uses keys of its own, and it only counts the en passant file
+// when a pawn of the side to move can capture en passant.
+func Key(b *board.Board) uint64 {
+	key := uint64(0)
+
+	for piece := WP; piece <= BK; piece++ {
+		// Polyglot orders the kinds black pawn, white pawn, black knight, ...
+		kind := 2 * (piece % 6)
+		if piece <= WK {
+			kind++
+		}
+
+		bb := b.Bitboards[piece]
+		for bb != 0 {
+			sq := bb.FirstOne()
+			key ^= random64[64*kind+8*(7-sq/8)+sq%8]
+		}
+	}
+
+	for i, right := range []uint{ShortW, LongW, ShortB, LongB} {
+		if uint(b.Castlings)&right != 0 {
+			key ^= random64[castlingOffset+i]
+		}
+	}
+
+	if b.EnPassant != -1 {
+		// The pawns of the side to move attacking the en passant square are
+		// found with the pawn attacks of the other side from that square
+		pawns := b.Bitboards[WP]
+		if b.SideToMove == color.BLACK {
+			pawns = b.Bitboards[BP]
+		}
+		if attacks.PawnAttacks[b.SideToMove.Opp()][b.EnPassant]&pawns != 0 {
+			key ^= random64[enPassantOffset+b.EnPassant%8]
+		}
+	}
+
+	if b.SideToMove == color.WHITE {
+		key ^= random64[turnOffset]
+	}
+
+	return key
+}
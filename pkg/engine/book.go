@@ -0,0 +1,47 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"log"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/book"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// applyBookOptions loads the opening book when OwnBook is set and BookFile
+// changed. A book that fails to load is reported once and not used.
+func (e *Engine) applyBookOptions() {
+	if !e.Options.OwnBook || e.Options.BookFile == e.bookFile {
+		return
+	}
+
+	e.book, e.bookFile = nil, e.Options.BookFile
+	if e.bookFile == "" {
+		return
+	}
+
+	bk, err := book.Open(util.MapPath(e.bookFile))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	e.book = bk
+}
+
+// bookMove picks a move from the opening book for the current position. The
+// book isn't used for infinite searches, which are meant for analysis.
+func (e *Engine) bookMove(params SearchParams) (move.Move, bool) {
+	if !e.Options.OwnBook || e.book == nil || params.Limits.Infinite {
+		return move.NoMove, false
+	}
+
+	b := params.Boards[len(params.Boards)-1]
+	if b.FullMoveCounter > e.Options.BookDepth {
+		return move.NoMove, false
+	}
+	return e.book.Pick(&b, book.WeightedRandom, nil)
+}
@@ -9,4 +9,24 @@ const (
 	MateScore  = 49_000
 	MateDepth  = 48_000
 	MaxKillers = 2
+
+	// Tablebase wins score below mates, minus the ply they're found at
+	TBWin      = 47_000
+	TBWinDepth = TBWin - MaxDepth
+)
+
+// Null move pruning parameters
+const (
+	NullMoveMinDepth          = 3   // Minimum remaining depth to try a null move
+	NullMoveBaseReduction     = 3   // Depth reduction applied to every null move search
+	NullMoveDepthDivisor      = 4   // One extra ply of reduction per this many plies of depth
+	NullMoveEvalDivisor       = 200 // One extra ply of reduction per this margin above beta
+	NullMoveMaxEvalReduction  = 3   // Cap on the reduction from the evaluation margin
+	NullMoveVerificationDepth = 12  // From this depth a fail-high is verified without null moves
+)
+
+// Aspiration window parameters
+const (
+	AspirationMinDepth = 4  // First iteration searched with a window around the last score
+	AspirationWindow   = 25 // Initial distance of the window bounds from the last score
 )
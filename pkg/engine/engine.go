@@ -8,11 +8,12 @@ import (
 	"context"
 	"time"
 
-	"github.com/Tecu23/argov2/internal/history"
 	"github.com/Tecu23/argov2/internal/reduction"
 	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/book"
 	"github.com/Tecu23/argov2/pkg/move"
-	"github.com/Tecu23/argov2/pkg/nnue"
+	"github.com/Tecu23/argov2/pkg/syzygy"
 )
 
 type mainLine struct {
@@ -23,46 +24,99 @@ type mainLine struct {
 }
 
 type Engine struct {
-	nodes          int64
 	Options        Options
 	mainLine       mainLine
+	lines          []mainLine // Best lines of the last finished MultiPV iteration
 	start          time.Time
 	progress       func(SearchInfo)
 	timeManager    *timeManager
 	cancel         context.CancelFunc
-	evaluator      nnue.Evaluator
 	tt             *TranspositionTable
+	ttSize         int // Size of tt in MB
 	reductionTable *reduction.Table
-	historyTable   *history.HistoryTable
-	killerMoves    [MaxDepth][MaxKillers]move.Move
+	threads        []*searchThread
+	book           *book.Book
+	bookFile       string // BookFile of the loaded book
+	evalFile       string // EvalFile of the loaded network
+	tb             *syzygy.Tablebase
+	tbPath         string      // SyzygyPath of the opened tablebases
+	tbRootMoves    []move.Move // Root moves kept by the tablebases, nil when all are searched
+	tbRootScore    int         // Score of the root result in the tablebases, used with tbRootMoves
+	tbProbeLimit   int         // Largest number of pieces probed during the search, 0 for none
 }
 
 func NewEngine(options Options) *Engine {
-	return &Engine{
+	e := &Engine{
 		Options:        options,
-		evaluator:      *nnue.NewEvaluator(),
-		tt:             NewTranspositionTable(32),
+		tt:             NewTranspositionTable(options.Hash),
+		ttSize:         options.Hash,
 		reductionTable: reduction.New(),
-		historyTable:   history.New(),
 	}
+	e.threads = []*searchThread{newSearchThread(e, 0)}
+	return e
 }
 
-func (e *Engine) Prepare() {}
+// Prepare applies changed options, so that a following search starts right away
+func (e *Engine) Prepare() {
+	e.applyOptions()
+}
+
+// applyOptions brings the engine state in line with its options. Threads and
+// MultiPV are read by every search, so only the table size, the network, the
+// opening book and the tablebases need work here.
+func (e *Engine) applyOptions() {
+	hash := min(max(e.Options.Hash, MinHash), MaxHash)
+	if hash != e.ttSize {
+		e.tt.Resize(hash)
+		e.ttSize = hash
+	}
+	e.applyNetworkOptions()
+	e.applyBookOptions()
+	e.applyTablebaseOptions()
+}
 
 // Search is the main entry point for starting a search
 func (e *Engine) Search(ctx context.Context, params SearchParams) SearchInfo {
 	e.start = time.Now()
+	e.applyOptions()
 	e.mainLine = mainLine{}
+	e.lines = nil
 	e.progress = params.Progress
 
-	// Get current position
-	currentBoard := params.Boards[len(params.Boards)-1]
+	// Book moves are played without searching
+	if mv, ok := e.bookMove(params); ok {
+		return SearchInfo{Time: time.Since(e.start), MainLine: []move.Move{mv}}
+	}
 
-	e.timeManager = newTimeManager(ctx, e.start, params.Limits, &currentBoard)
+	e.timeManager = newTimeManager(
+		ctx,
+		e.start,
+		params.Limits,
+		&params.Boards[len(params.Boards)-1],
+		time.Duration(e.Options.MoveOverhead)*time.Millisecond,
+		params.PonderHit,
+	)
 	defer e.timeManager.Close()
 
 	// Start actual search
-	return e.search(ctx, &currentBoard, e.timeManager)
+	return e.search(ctx, params.Boards, e.timeManager)
+}
+
+// rootBoard returns the current position of the game with the repetition
+// history seeded from the positions played before it. Every search thread
+// needs its own copy, since the history and the undo records grow as the
+// search makes moves.
+func rootBoard(boards []board.Board) board.Board {
+	b := boards[len(boards)-1].CopyBoard()
+
+	// Extra capacity is reserved so the search line can grow without reallocating
+	history := make([]uint64, 0, len(boards)+2*MaxDepth)
+	for i := 0; i < len(boards)-1; i++ {
+		history = append(history, boards[i].Hash())
+	}
+	b.SetHistory(history)
+
+	return b
 }
 
 func (e *Engine) Clear() {
@@ -71,14 +125,47 @@ func (e *Engine) Clear() {
 
 // createSearchInfo creates a SearchInfo struct from current engine state
 func (e *Engine) createSearchInfo() SearchInfo {
+	info := e.lineSearchInfo(e.mainLine)
+	if e.Options.MultiPV > 1 {
+		info.MultiPV = 1
+	}
+	return info
+}
+
+// lineSearchInfo creates a SearchInfo struct for a single line. When the root
+// is in the tablebases, the search only decides among moves of the same
+// result, so that result is reported unless a mate was found.
+func (e *Engine) lineSearchInfo(line mainLine) SearchInfo {
+	score := line.score
+	if e.tbRootMoves != nil && mateInMoves(score) == 0 {
+		score = e.tbRootScore
+	}
+
 	return SearchInfo{
 		Score: UciScore{
-			Centipawns: e.mainLine.score,
-			Mate:       0,
+			Centipawns: score,
+			Mate:       mateInMoves(score),
 		},
-		Depth:    e.mainLine.depth,
-		Nodes:    e.mainLine.nodes,
+		Depth:    line.depth,
+		Nodes:    line.nodes,
 		Time:     time.Since(e.start),
-		MainLine: e.mainLine.moves,
+		HashFull: e.tt.HashFull(),
+		TBHits:   e.tbHits(),
+		MainLine: line.moves,
+	}
+}
+
+// reportProgress reports the result of a finished iteration. In MultiPV mode
+// every line is reported with its rank, as long as all lines are up to date.
+func (e *Engine) reportProgress() {
+	if len(e.lines) < 2 || e.lines[0].depth != e.mainLine.depth {
+		e.progress(e.createSearchInfo())
+		return
+	}
+
+	for i, line := range e.lines {
+		info := e.lineSearchInfo(line)
+		info.MultiPV = i + 1
+		e.progress(info)
 	}
 }
@@ -3,13 +3,7 @@
 
 package engine
 
-import (
-	"sort"
-
-	"github.com/Tecu23/argov2/pkg/board"
-	"github.com/Tecu23/argov2/pkg/move"
-	"github.com/Tecu23/argov2/pkg/nnue"
-)
+import "github.com/Tecu23/argov2/pkg/move"
 
 func winIn(height int) int {
 	return MateScore - height
@@ -19,12 +13,7 @@ func lossIn(height int) int {
 	return -MateScore + height
 }
 
-type MoveScore struct {
-	move  move.Move
-	score int
-}
-
-func (e *Engine) updateKillers(mv move.Move, ply int) {
+func (t *searchThread) updateKillers(mv move.Move, ply int) {
 	if ply >= MaxDepth {
 		return
 	}
@@ -35,74 +24,80 @@ func (e *Engine) updateKillers(mv move.Move, ply int) {
 
 	// Don't store a move that's already a killer at this ply
 	for i := 0; i < MaxKillers; i++ {
-		if e.killerMoves[ply][i] == mv {
+		if t.killerMoves[ply][i] == mv {
 			return
 		}
 	}
 
 	// Shift existing killers and insert new one at first position
 	for i := MaxKillers - 1; i > 0; i-- {
-		e.killerMoves[ply][i] = e.killerMoves[ply][i-1]
+		t.killerMoves[ply][i] = t.killerMoves[ply][i-1]
 	}
-	e.killerMoves[ply][0] = mv
+	t.killerMoves[ply][0] = mv
 }
 
-func (e *Engine) orderMoves(
-	moves []move.Move,
-	b *board.Board,
-	ttMove move.Move,
-	ply int,
-) []move.Move {
-	scores := make([]MoveScore, len(moves))
-	stm := b.SideToMove
-
-	for i, mv := range moves {
-		score := 0
-
-		// TT move gets highest priority
-		if mv == ttMove {
-			score = 2_000_000
-		} else if mv.IsCapture() {
-			// MVV-LVA scoring
-			victim := b.GetPieceAt(mv.GetTargetSquare())
-			aggressor := b.GetPieceAt(mv.GetSourceSquare())
-			score = 1_000_000 + (nnue.GetPieceValue(victim) - nnue.GetPieceValue(aggressor)/10)
-		} else {
-			for j := 0; j < MaxKillers; j++ {
-				if mv == e.killerMoves[ply][j] {
-					score = 900_000 - j*1000
-					break
-				}
-			}
-
-			if score == 0 {
-				score = e.historyTable.Get(stm, mv.GetSourceSquare(), mv.GetTargetSquare())
-			}
-		}
+// counterMove returns the quiet move that last refuted the move played at the
+// ply before, if any.
+func (t *searchThread) counterMove(ply int) move.Move {
+	if ply == 0 {
+		return move.NoMove
+	}
+	prev := t.moveStack[ply-1]
+	if prev == move.NoMove {
+		return move.NoMove
+	}
+	return t.counterMoves[prev.GetMovingPiece()][prev.GetTargetSquare()]
+}
 
-		scores[i] = MoveScore{mv, score}
+// updateCounterMove stores mv as the refutation of the move played at the ply
+// before.
+func (t *searchThread) updateCounterMove(mv move.Move, ply int) {
+	if ply == 0 {
+		return
+	}
+	if prev := t.moveStack[ply-1]; prev != move.NoMove {
+		t.counterMoves[prev.GetMovingPiece()][prev.GetTargetSquare()] = mv
 	}
+}
 
-	// Sort moves by score
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].score > scores[j].score
-	})
+// isMateScore reports whether the score announces a forced mate for either side.
+func isMateScore(score int) bool {
+	return score >= MateDepth || score <= -MateDepth
+}
 
-	// Extract sorted moves
-	sortedMoves := make([]move.Move, len(moves))
-	for i, ms := range scores {
-		sortedMoves[i] = ms.move
+// scoreToTT converts a mate or tablebase win score, which counts plies from
+// the root, into a score counting plies from the current node, so that the
+// entry stays valid when the position is reached again at a different ply.
+func scoreToTT(score, ply int) int {
+	if score >= TBWinDepth {
+		return score + ply
 	}
-
-	return sortedMoves
+	if score <= -TBWinDepth {
+		return score - ply
+	}
+	return score
 }
 
-func adjustScore(score, ply int) int {
-	if score >= MateScore-MaxDepth {
+// scoreFromTT converts a score read from the transposition table back into a
+// score relative to the root. It's the inverse of scoreToTT.
+func scoreFromTT(score, ply int) int {
+	if score >= TBWinDepth {
 		return score - ply
 	}
-	if score <= -MateScore+MaxDepth {
+	if score <= -TBWinDepth {
 		return score + ply
 	}
 	return score
 }
+
+// mateInMoves returns the number of moves until mate for a mate score. It's
+// positive when the side to move mates and negative when it gets mated.
+func mateInMoves(score int) int {
+	if score >= MateDepth {
+		return (MateScore - score + 1) / 2
+	}
+	if score <= -MateDepth {
+		return -(MateScore + score) / 2
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import "testing"
+
+func TestScoreTTRoundTrip(t *testing.T) {
+	scores := []int{
+		0, 35, -1200,
+		TBWinDepth - 1, -TBWinDepth + 1, // Largest scores left as they are
+		TBWinDepth, -TBWinDepth,
+		TBWin - 3, -TBWin + 8,
+		MateDepth - 1, MateDepth,
+		winIn(1), winIn(12), winIn(MaxDepth - 1),
+		lossIn(0), lossIn(2), lossIn(MaxDepth - 1),
+	}
+
+	for _, score := range scores {
+		for _, ply := range []int{0, 1, 7, 40, MaxDepth - 1} {
+			if got := scoreFromTT(scoreToTT(score, ply), ply); got != score {
+				t.Errorf("score %d at ply %d comes back from the table as %d", score, ply, got)
+			}
+		}
+	}
+
+	// Scores below the tablebase wins don't depend on the ply
+	for _, score := range []int{0, 35, TBWinDepth - 1, -TBWinDepth + 1} {
+		if got := scoreToTT(score, 9); got != score {
+			t.Errorf("scoreToTT(%d, 9) = %d, want it unchanged", score, got)
+		}
+	}
+}
+
+// TestScoreTTDistance checks that a mate or tablebase win stored at one ply
+// and read at another keeps its distance from the node it was stored for.
+func TestScoreTTDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    int // Score at storePly, counted from the root
+		storePly int
+		probePly int
+		expected int
+	}{
+		{"Mate deeper", winIn(7), 4, 10, winIn(13)},
+		{"Mate closer", winIn(9), 6, 1, winIn(4)},
+		{"Mated", lossIn(6), 3, 5, lossIn(8)},
+		{"TB win", TBWin - 12, 10, 2, TBWin - 4},
+		{"TB loss", -TBWin + 5, 5, 20, -TBWin + 20},
+		{"Plain score", 240, 3, 30, 240},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreFromTT(scoreToTT(tt.score, tt.storePly), tt.probePly); got != tt.expected {
+				t.Errorf("score %d stored at ply %d and read at ply %d = %d, want %d",
+					tt.score, tt.storePly, tt.probePly, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMateInMoves(t *testing.T) {
+	tests := []struct {
+		score    int
+		expected int
+	}{
+		{winIn(1), 1}, // The side to move mates with its first move
+		{winIn(3), 2},
+		{winIn(4), 2}, // Odd and even plies count the same moves when winning
+		{winIn(5), 3},
+		{lossIn(2), -1}, // Mated after the reply to the first move
+		{lossIn(4), -2},
+		{lossIn(6), -3},
+		{TBWin - 5, 0}, // Tablebase wins aren't mates
+		{-TBWin + 5, 0},
+		{MateDepth - 1, 0},
+		{0, 0},
+		{450, 0},
+	}
+
+	for _, tt := range tests {
+		if got := mateInMoves(tt.score); got != tt.expected {
+			t.Errorf("mateInMoves(%d) = %d, want %d", tt.score, got, tt.expected)
+		}
+		if isMateScore(tt.score) != (tt.expected != 0) {
+			t.Errorf("isMateScore(%d) = %v", tt.score, isMateScore(tt.score))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"log"
+
+	"github.com/Tecu23/argov2/pkg/nnue"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// applyNetworkOptions loads the network in EvalFile when it changed, or the
+// embedded one when EvalFile was cleared. Options are only applied between
+// searches, and the evaluators of the threads switch to the new network when
+// they're reset for the next one. A network that fails to load is reported
+// once and the current network stays in use.
+func (e *Engine) applyNetworkOptions() {
+	if e.Options.EvalFile == e.evalFile {
+		return
+	}
+	e.evalFile = e.Options.EvalFile
+
+	var err error
+	if e.evalFile == "" {
+		err = nnue.LoadDefault()
+	} else {
+		err = nnue.LoadFile(util.MapPath(e.evalFile))
+	}
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if desc := nnue.LoadedHeader().Description; desc != "" {
+		log.Printf("loaded network %q (%v), checksum %08x", e.evalFile, desc, nnue.Checksum())
+		return
+	}
+	log.Printf("loaded network %q, checksum %08x", e.evalFile, nnue.Checksum())
+}
@@ -3,8 +3,42 @@
 
 package engine
 
-type Options struct{}
+// Limits of the engine settings
+const (
+	MinHash         = 1
+	MaxHash         = 65536
+	MaxThreads      = 256
+	MaxMultiPV      = 256
+	MaxMoveOverhead = 5000
+	MaxBookDepth    = 200
+
+	MaxSyzygyProbeLimit = 7
+)
+
+// Options holds the engine settings that can be changed through UCI options.
+// They're applied when the engine gets ready and at the start of every search.
+type Options struct {
+	Hash         int    // Size of the transposition table in MB
+	Threads      int    // Number of search threads
+	MultiPV      int    // Number of best root moves searched and reported
+	MoveOverhead int    // Time in milliseconds kept back for communication delays
+	EvalFile     string // NNUE network file, the embedded network is used when empty
+	OwnBook      bool   // Play moves from the opening book in BookFile
+	BookFile     string // Polyglot opening book
+	BookDepth    int    // Last move number at which the book is used
+
+	SyzygyPath       string // Directories of the Syzygy tablebases
+	SyzygyProbeLimit int    // Largest number of pieces probed in the tablebases
+}
 
 func NewOptions() Options {
-	return Options{}
+	return Options{
+		Hash:         32,
+		Threads:      1,
+		MultiPV:      1,
+		MoveOverhead: 300,
+		BookDepth:    20,
+
+		SyzygyProbeLimit: MaxSyzygyProbeLimit,
+	}
 }
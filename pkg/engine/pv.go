@@ -0,0 +1,42 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import "github.com/Tecu23/argov2/pkg/move"
+
+// pvTable is a triangular table collecting the principal variation. Row ply
+// holds the best line found from that ply onward, so whenever a move raises
+// alpha it is stored in front of the line of its child. After a finished
+// iteration row 0 holds the full principal variation.
+type pvTable struct {
+	moves  [MaxDepth + 1][MaxDepth + 1]move.Move
+	length [MaxDepth + 1]int
+}
+
+// clear empties the line at the given ply. It has to be called when a node is
+// entered so that stale moves from a sibling subtree are not picked up.
+func (pv *pvTable) clear(ply int) {
+	if ply <= MaxDepth {
+		pv.length[ply] = ply
+	}
+}
+
+// update stores mv as the best move at ply followed by the line of the child.
+func (pv *pvTable) update(ply int, mv move.Move) {
+	if ply >= MaxDepth {
+		return
+	}
+
+	pv.moves[ply][ply] = mv
+	n := max(pv.length[ply+1], ply+1)
+	copy(pv.moves[ply][ply+1:n], pv.moves[ply+1][ply+1:n])
+	pv.length[ply] = n
+}
+
+// line returns a copy of the principal variation from the root.
+func (pv *pvTable) line() []move.Move {
+	line := make([]move.Move, pv.length[0])
+	copy(line, pv.moves[0][:pv.length[0]])
+	return line
+}
@@ -6,6 +6,9 @@ package engine
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"sync"
 
 	"github.com/Tecu23/argov2/internal/reduction"
 	. "github.com/Tecu23/argov2/internal/types"
@@ -13,14 +16,50 @@ import (
 	"github.com/Tecu23/argov2/pkg/move"
 )
 
-// search performs the actual search logic
-func (e *Engine) search(ctx context.Context, b *board.Board, tm *timeManager) SearchInfo {
-	e.nodes = 0
+// search runs a Lazy SMP search: every thread searches the same root position
+// with its own board and tables and they cooperate only through the shared
+// transposition table. The main thread drives reporting and the time manager,
+// and the helpers are stopped as soon as it's done.
+func (e *Engine) search(ctx context.Context, boards []board.Board, tm *timeManager) SearchInfo {
 	e.tt.NewSearch()
-	e.historyTable.Clear()
-	e.killerMoves = [MaxDepth][MaxKillers]move.Move{}
+	e.prepareThreads()
+
+	root := rootBoard(boards)
+	e.rankRootMoves(&root)
+
+	helperCtx, stopHelpers := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, t := range e.threads[1:] {
+		wg.Add(1)
+		go func(t *searchThread) {
+			defer wg.Done()
+			b := rootBoard(boards)
+			t.iterativeDeepening(helperCtx, &b, tm)
+		}(t)
+	}
+
+	b := rootBoard(boards)
+	bestMove := e.threads[0].iterativeDeepening(ctx, &b, tm)
 
-	e.evaluator.Reset(b)
+	stopHelpers()
+	wg.Wait()
+
+	searchInfo := e.createSearchInfo()
+	// Ensure we have a move to return
+	if len(searchInfo.MainLine) == 0 && bestMove != move.NoMove {
+		searchInfo.MainLine = []move.Move{bestMove}
+	}
+
+	return searchInfo
+}
+
+// iterativeDeepening searches the root position with increasing depth until a
+// limit is reached or the search is stopped, and returns the best move found.
+// Only the main thread reports progress; helper threads with an odd id start
+// one ply deeper so that the threads spread over different depths.
+func (t *searchThread) iterativeDeepening(ctx context.Context, b *board.Board, tm *timeManager) move.Move {
+	e := t.engine
+	t.evaluator.Reset(b)
 
 	var bestMove move.Move
 	var bestScore int
@@ -31,35 +70,54 @@ func (e *Engine) search(ctx context.Context, b *board.Board, tm *timeManager) Se
 		maxDepth = tm.limits.Depth
 	}
 
+	// Only the main thread searches more than the best line
+	multiPV := 1
+	if t.isMain() {
+		multiPV = max(e.Options.MultiPV, 1)
+	}
+
 	// Iterative deepeing
-	for depth := 1; depth <= maxDepth; depth++ {
+	for depth := 1 + t.id%2; depth <= maxDepth; depth++ {
 		if tm.IsDone() || ctx.Err() != nil {
 			break
 		}
 
 		// Report current search depth
-		if e.progress != nil {
+		if t.isMain() && e.progress != nil {
 			info := e.createSearchInfo()
 			info.Depth = depth
 			e.progress(info)
 		}
 
-		score, mv := e.searchRoot(ctx, b, depth, tm)
-		if mv != move.NoMove {
-			// Store best move and score
-			bestMove = mv
-			bestScore = score
+		score, mv := t.aspirationSearch(ctx, b, depth, bestScore, tm)
+		if mv == move.NoMove {
+			continue
+		}
+
+		// Store best move and score
+		bestMove = mv
+		bestScore = score
+
+		if !t.isMain() {
+			continue
+		}
+
+		// Update search info
+		e.mainLine = mainLine{
+			moves: t.rootLine(bestMove),
+			score: bestScore,
+			depth: depth,
+			nodes: e.nodes(),
+		}
 
-			// Update search info
-			e.mainLine.moves = []move.Move{bestMove}
-			e.mainLine.score = bestScore
-			e.mainLine.depth = depth
-			e.mainLine.nodes = e.nodes
+		if multiPV > 1 {
+			t.searchMultiPV(ctx, b, depth, multiPV, tm)
+			bestMove, bestScore = e.mainLine.moves[0], e.mainLine.score
 		}
 
 		// Report progress
 		if e.progress != nil {
-			e.progress(e.createSearchInfo())
+			e.reportProgress()
 		}
 
 		// Check if we should stop
@@ -68,84 +126,196 @@ func (e *Engine) search(ctx context.Context, b *board.Board, tm *timeManager) Se
 		}
 
 		// Update time manager
-		tm.OnNodesChanged(int(e.nodes))
+		tm.OnNodesChanged(int(e.nodes()))
 
-		// If we found a forced mate, no need to search deeper
-		if bestScore > MateScore-MaxDepth || bestScore < -MateScore+MaxDepth {
+		if tm.limits.Mate > 0 {
+			// With "go mate N" only a mate in at most N moves ends the search
+			if mate := mateInMoves(bestScore); mate > 0 && mate <= tm.limits.Mate {
+				break
+			}
+		} else if isMateScore(bestScore) {
+			// If we found a forced mate, no need to search deeper
 			break
 		}
 	}
 
-	searchInfo := e.createSearchInfo()
-	// Ensure we have a move to return
-	if len(searchInfo.MainLine) == 0 && bestMove != move.NoMove {
-		searchInfo.MainLine = []move.Move{bestMove}
+	return bestMove
+}
+
+// searchMultiPV completes an iteration of a MultiPV search once the best line
+// is known. Every further line is searched with the root moves of the lines
+// before it left out, so the lines hold the best root moves in order. Only a
+// finished set of lines replaces the lines of the previous iteration.
+func (t *searchThread) searchMultiPV(
+	ctx context.Context,
+	b *board.Board,
+	depth, multiPV int,
+	tm *timeManager,
+) {
+	e := t.engine
+	lines := []mainLine{e.mainLine}
+
+	for len(lines) < multiPV {
+		t.skipMoves = append(t.skipMoves, lines[len(lines)-1].moves[0])
+
+		// Center the aspiration window on the score of the same line last time
+		prevScore := lines[len(lines)-1].score
+		if len(e.lines) > len(lines) {
+			prevScore = e.lines[len(lines)].score
+		}
+
+		score, mv := t.aspirationSearch(ctx, b, depth, prevScore, tm)
+		if mv == move.NoMove {
+			// Aborted, or there are fewer root moves than lines
+			break
+		}
+
+		lines = append(lines, mainLine{
+			moves: t.rootLine(mv),
+			score: score,
+			depth: depth,
+		})
 	}
+	t.skipMoves = t.skipMoves[:0]
 
-	return searchInfo
+	if ctx.Err() != nil || tm.IsDone() {
+		return
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].score > lines[j].score
+	})
+
+	nodes := e.nodes()
+	for i := range lines {
+		lines[i].nodes = nodes
+	}
+
+	e.lines = lines
+	e.mainLine = lines[0]
+}
+
+// rootLine returns the principal variation of the last root search, which
+// starts with the given root move.
+func (t *searchThread) rootLine(mv move.Move) []move.Move {
+	line := t.pv.line()
+	if len(line) == 0 || line[0] != mv {
+		return []move.Move{mv}
+	}
+	return line
 }
 
-// searchRoot performs alpha-beta search at the root level
-func (e *Engine) searchRoot(
+// aspirationSearch searches the root with a narrow window around the score of
+// the previous iteration. When the result falls outside the window, the bound
+// that failed is widened and the root is searched again, until the score lands
+// inside the window or the window spans the whole score range. Shallow depths
+// and mate scores are searched with a full window straight away.
+func (t *searchThread) aspirationSearch(
 	ctx context.Context,
 	b *board.Board,
-	depth int,
+	depth, prevScore int,
 	tm *timeManager,
 ) (int, move.Move) {
-	alpha := -Infinity
-	beta := Infinity
+	alpha, beta := -Infinity, Infinity
+	window := AspirationWindow
+
+	if depth >= AspirationMinDepth && !isMateScore(prevScore) {
+		alpha = max(prevScore-window, -Infinity)
+		beta = min(prevScore+window, Infinity)
+	}
+
+	for {
+		score, mv := t.searchRoot(ctx, b, depth, alpha, beta, tm)
+		if mv == move.NoMove || (alpha == -Infinity && beta == Infinity) {
+			return score, mv
+		}
+
+		window *= 2
+		switch {
+		case score <= alpha:
+			// Fail low: the best move is unreliable, widen downwards
+			beta = (alpha + beta) / 2
+			alpha = max(score-window, -Infinity)
+		case score >= beta:
+			// Fail high: widen upwards
+			beta = min(score+window, Infinity)
+		default:
+			return score, mv
+		}
+	}
+}
+
+// searchRoot performs alpha-beta search at the root level within the given window
+func (t *searchThread) searchRoot(
+	ctx context.Context,
+	b *board.Board,
+	depth, alpha, beta int,
+	tm *timeManager,
+) (int, move.Move) {
+	t.pv.clear(0)
+
 	var bestMove move.Move
 	originalAlpha := alpha
 
 	// Generate moves at root
-	moves := b.GenerateMoves()
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
 
 	// Check for single legal move - if only 1 move is available, return it immediately
-	if len(moves) == 1 {
-		cpy := b.CopyBoard()
-		if cpy.MakeMove(moves[0], board.AllMoves) {
-			e.evaluator.ProcessMove(&cpy, moves[0])
-			score := e.evaluator.Evaluate(&cpy)
-			e.evaluator.PopAccumulation()
-			return score, moves[0]
-		}
+	if moves.Len() == 1 && len(t.skipMoves) == 0 {
+		mv := moves.Get(0)
+		b.MakeMove(mv, board.AllMoves)
+		t.evaluator.ProcessMove(b, mv)
+		score := t.evaluator.Evaluate(b)
+		t.evaluator.PopAccumulation()
+		b.UnmakeMove()
+		t.pv.update(0, mv)
+		return score, mv
 	}
 
 	var ttMove move.Move
-	if entry, ok := e.tt.Probe(b.Hash()); ok {
+	if entry, ok := t.engine.tt.Probe(b.Hash()); ok {
 		ttMove = entry.BestMove
 	}
 
-	moves = e.orderMoves(moves, b, ttMove, 0)
+	mp := &t.pickers[0]
+	mp.Init(b, t.historyTable, ttMove, t.killerMoves[0], move.NoMove)
 
 	bestScore := -Infinity
 	moveCount := 0
 
-	for i, mv := range moves {
-		copyB := b.CopyBoard()
-		if !copyB.MakeMove(mv, board.AllMoves) {
+	for mv := mp.Next(); mv != move.NoMove; mv = mp.Next() {
+		// Leave out the root moves of better MultiPV lines and the ones
+		// spoiling a tablebase result
+		if slices.Contains(t.skipMoves, mv) || !t.engine.isRootMove(mv) {
 			continue
 		}
 
-		e.evaluator.ProcessMove(&copyB, mv)
+		if !b.MakeMove(mv, board.AllMoves) {
+			continue
+		}
+
+		t.evaluator.ProcessMove(b, mv)
+		t.moveStack[0] = mv
 		moveCount++
 
 		var score int
 
 		// For the first move or promising moves, do a full-window search
-		if i == 0 {
-			score = -e.alphaBeta(ctx, &copyB, depth-1, -beta, -alpha, 1, tm)
+		if moveCount == 1 {
+			score = -t.alphaBeta(ctx, b, depth-1, -beta, -alpha, 1, tm)
 		} else {
 			// Use zero-window search for other moves
-			score = -e.alphaBeta(ctx, &copyB, depth-1, -alpha-1, -alpha, 1, tm)
+			score = -t.alphaBeta(ctx, b, depth-1, -alpha-1, -alpha, 1, tm)
 
 			// If the score exceeds alpha but is below beta, re-search with full window
 			if score > alpha && score < beta {
-				score = -e.alphaBeta(ctx, &copyB, depth-1, -beta, -alpha, 1, tm)
+				score = -t.alphaBeta(ctx, b, depth-1, -beta, -alpha, 1, tm)
 			}
 		}
 
-		e.evaluator.PopAccumulation()
+		t.evaluator.PopAccumulation()
+		b.UnmakeMove()
 
 		// Check for search abort
 		if ctx.Err() != nil || tm.IsDone() {
@@ -159,14 +329,7 @@ func (e *Engine) searchRoot(
 
 			if score > alpha {
 				alpha = score
-
-				// For the main variation, we store the move in the PV
-				if e.mainLine.moves == nil {
-					e.mainLine.moves = make([]move.Move, 1)
-					e.mainLine.moves[0] = mv
-				} else if len(e.mainLine.moves) > 0 {
-					e.mainLine.moves[0] = mv
-				}
+				t.pv.update(0, mv)
 
 				if alpha >= beta {
 					break
@@ -178,32 +341,36 @@ func (e *Engine) searchRoot(
 	// If no legal moves were found
 	if moveCount == 0 {
 		if b.InCheck() {
-			return -MateScore, move.NoMove
+			return lossIn(0), move.NoMove
 		}
 
 		return 0, move.NoMove
 	}
 
-	// Store in TT
-	flag := TTExact
-	if bestScore <= originalAlpha {
-		flag = TTAlpha
-	} else if bestScore >= beta {
-		flag = TTBeta
+	// Store in TT, unless better root moves were left out
+	if len(t.skipMoves) == 0 {
+		flag := TTExact
+		if bestScore <= originalAlpha {
+			flag = TTAlpha
+		} else if bestScore >= beta {
+			flag = TTBeta
+		}
+		t.engine.tt.Store(b.Hash(), scoreToTT(bestScore, 0), depth, flag, bestMove)
 	}
-	e.tt.Store(b.Hash(), alpha, depth, flag, bestMove)
 
 	return bestScore, bestMove
 }
 
 // alphaBeta performs the main alpha-beta search with Principal Variation Search
-func (e *Engine) alphaBeta(
+func (t *searchThread) alphaBeta(
 	ctx context.Context,
 	b *board.Board,
 	depth, alpha, beta, ply int,
 	tm *timeManager,
 ) int {
-	if (e.nodes & 1023) == 0 {
+	t.pv.clear(ply)
+
+	if (t.nodes.Load() & 1023) == 0 {
 		if ctx.Err() != nil || tm.IsDone() {
 			// This ensures the move won't be selected
 			// as it will always be worse than any real evaluation
@@ -215,7 +382,25 @@ func (e *Engine) alphaBeta(
 	}
 
 	// Increment node counter
-	e.nodes++
+	t.nodes.Add(1)
+
+	// Repetitions and the fifty-move rule are draws
+	if b.IsRepetition() || b.IsFiftyMoveDraw() {
+		return 0
+	}
+
+	// The search line can't grow past the size of the per-ply tables
+	if ply >= MaxDepth {
+		return t.evaluator.Evaluate(b)
+	}
+
+	// Mate distance pruning: even mating right here can't beat a shorter mate
+	// already found, and being mated here can't be worse than alpha
+	alpha = max(alpha, lossIn(ply))
+	beta = min(beta, winIn(ply+1))
+	if alpha >= beta {
+		return alpha
+	}
 
 	originalAlpha := alpha
 	isPV := beta > alpha+1 // Check if this is a PV node
@@ -223,15 +408,15 @@ func (e *Engine) alphaBeta(
 	// TT Lookup
 	hash := b.Hash()
 	var ttMove move.Move
-	if entry, ok := e.tt.Probe(hash); ok {
+	if entry, ok := t.engine.tt.Probe(hash); ok {
 		ttMove = entry.BestMove
 
 		// We can use TT cutoffs in non-PV nodes when depth is sufficient
 		if !isPV && entry.Depth >= depth {
-			score := adjustScore(entry.Score, ply)
+			score := scoreFromTT(entry.Score, ply)
 			switch entry.Flag {
 			case TTExact:
-				return entry.Score
+				return score
 			case TTAlpha:
 				if score <= alpha {
 					return alpha
@@ -244,46 +429,73 @@ func (e *Engine) alphaBeta(
 		}
 	}
 
+	// Tablebase cutoff: a result that's exact, or a bound outside the window,
+	// ends the search of the node
+	if ply > 0 {
+		if wdl, ok := t.probeWDL(b); ok {
+			score, flag := tbScore(wdl, ply)
+			if flag == TTExact ||
+				(flag == TTBeta && score >= beta) ||
+				(flag == TTAlpha && score <= alpha) {
+				t.engine.tt.Store(hash, scoreToTT(score, ply), min(depth+6, MaxDepth-1), flag, move.NoMove)
+				return score
+			}
+		}
+	}
+
+	inCheck := b.InCheck()
+
 	// Check extension
-	if b.InCheck() {
+	if inCheck {
 		depth++
 	}
 
 	// Check for terminal positions
 	if b.IsCheckmate() {
-		return -MateScore + int(e.nodes) // Prefer shorter mates
+		return lossIn(ply) // Prefer shorter mates
 	}
 
 	// Base case: evaluate leaf nodes
 	if depth <= 0 {
-		return e.quiescence(ctx, b, alpha, beta, ply, tm)
+		return t.quiescence(ctx, b, alpha, beta, ply, tm)
 	}
 
-	// Generate moves
-	moves := b.GenerateMoves()
-	moves = e.orderMoves(moves, b, ttMove, ply)
+	// Null move pruning. Skipped in check, right after another null move, in
+	// pawn-only endings where zugzwang is likely and when beta is a mate score
+	if !isPV && !inCheck &&
+		depth >= NullMoveMinDepth &&
+		ply >= t.nullMoveMinPly &&
+		!b.IsAfterNullMove() &&
+		b.HasNonPawnMaterial(b.SideToMove) &&
+		!isMateScore(beta) {
+		if score, ok := t.nullMoveSearch(ctx, b, depth, beta, ply, tm); ok {
+			return score
+		}
+	}
+
+	mp := &t.pickers[ply]
+	mp.Init(b, t.historyTable, ttMove, t.killerMoves[ply], t.counterMove(ply))
 
 	hasLegalMoves := false
 	var bestMove move.Move
 	bestScore := -Infinity
 	moveCount := 0
-	inCheck := b.InCheck()
 
-	// Search all moves {
-	for i, mv := range moves {
-		copyB := b.CopyBoard()
-		if !copyB.MakeMove(mv, board.AllMoves) {
+	// Search all moves
+	for mv := mp.Next(); mv != move.NoMove; mv = mp.Next() {
+		if !b.MakeMove(mv, board.AllMoves) {
 			continue
 		}
 
-		e.evaluator.ProcessMove(&copyB, mv)
+		t.evaluator.ProcessMove(b, mv)
+		t.moveStack[ply] = mv
 
 		hasLegalMoves = true
 		moveCount++
 
 		var score int
 		isCapture := mv.IsCapture()
-		givesCheck := copyB.InCheck()
+		givesCheck := b.InCheck()
 
 		reduct := 0
 		if depth >= reduction.MinDepthForReduction &&
@@ -291,20 +503,16 @@ func (e *Engine) alphaBeta(
 			!inCheck && !isCapture &&
 			!mv.IsPromotion() && !givesCheck {
 
-			// Get history score for this move
-			historyScore := e.historyTable.Get(
-				b.SideToMove,
-				mv.GetSourceSquare(),
-				mv.GetTargetSquare(),
-			)
+			// Get history score for this move, of the side that made it
+			historyScore := t.historyTable.Score(mv)
 
 			// Calculate reduction with adjustments
-			reduct = e.reductionTable.GetWithAdjustments(depth, moveCount, isPV, historyScore)
+			reduct = t.engine.reductionTable.GetWithAdjustments(depth, moveCount, isPV, historyScore)
 
 			// Additional dynamic adjustments
 
 			// 1. Reduce less for killer moves
-			if mv == e.killerMoves[ply][0] || mv == e.killerMoves[ply][1] {
+			if mv == t.killerMoves[ply][0] || mv == t.killerMoves[ply][1] {
 				reduct = max(0, reduct-1)
 			}
 
@@ -315,55 +523,58 @@ func (e *Engine) alphaBeta(
 		}
 
 		// PVS logic
-		if i == 0 {
+		if moveCount == 1 {
 			// Full window search for first move
-			score = -e.alphaBeta(ctx, &copyB, depth-1, -beta, -alpha, ply+1, tm)
+			score = -t.alphaBeta(ctx, b, depth-1, -beta, -alpha, ply+1, tm)
 		} else {
 			// Try with zero window for non-first moves
 			if reduct > 0 {
 				// Reduced depth zero window search
-				score = -e.alphaBeta(ctx, &copyB, depth-1-reduct, -alpha-1, -alpha, ply+1, tm)
+				score = -t.alphaBeta(ctx, b, depth-1-reduct, -alpha-1, -alpha, ply+1, tm)
 			} else {
 				// Normal depth zero window search
-				score = -e.alphaBeta(ctx, &copyB, depth-1, -alpha-1, -alpha, ply+1, tm)
+				score = -t.alphaBeta(ctx, b, depth-1, -alpha-1, -alpha, ply+1, tm)
 			}
 
 			if score > alpha && reduct > 0 {
-				score = -e.alphaBeta(ctx, &copyB, depth-1, -alpha-1, -alpha, ply+1, tm)
+				score = -t.alphaBeta(ctx, b, depth-1, -alpha-1, -alpha, ply+1, tm)
 			}
 
 			// If still promising, do a full-window search
 			if score > alpha && score < beta {
-				score = -e.alphaBeta(ctx, &copyB, depth-1, -beta, -alpha, ply+1, tm)
+				score = -t.alphaBeta(ctx, b, depth-1, -beta, -alpha, ply+1, tm)
 			}
 		}
 
-		e.evaluator.PopAccumulation()
+		t.evaluator.PopAccumulation()
+		b.UnmakeMove()
 
 		if score > bestScore {
 			bestScore = score
 			bestMove = mv
 			if score > alpha {
 				if !isCapture && ply < MaxDepth {
-					e.historyTable.Update(
-						copyB.SideToMove,
+					t.historyTable.Update(
+						b.SideToMove,
 						mv.GetSourceSquare(),
 						mv.GetTargetSquare(),
 						1,
 					)
 				}
 				alpha = score
+				t.pv.update(ply, mv)
 				if alpha >= beta {
 					if !isCapture && ply < MaxDepth {
-						e.updateKillers(mv, ply)
-						e.historyTable.Update(
-							copyB.SideToMove,
+						t.updateKillers(mv, ply)
+						t.updateCounterMove(mv, ply)
+						t.historyTable.Update(
+							b.SideToMove,
 							mv.GetSourceSquare(),
 							mv.GetTargetSquare(),
 							depth,
 						)
 					}
-					e.tt.Store(hash, beta, depth, TTBeta, mv)
+					t.engine.tt.Store(hash, scoreToTT(beta, ply), depth, TTBeta, mv)
 					return beta
 				}
 			}
@@ -372,8 +583,8 @@ func (e *Engine) alphaBeta(
 
 	// Check for chechmate/stalemate
 	if !hasLegalMoves {
-		if b.InCheck() {
-			return -MateScore + int(e.nodes)
+		if inCheck {
+			return lossIn(ply)
 		}
 		return 0
 	}
@@ -383,24 +594,79 @@ func (e *Engine) alphaBeta(
 	if bestScore <= originalAlpha {
 		flag = TTAlpha
 	}
-	e.tt.Store(hash, bestScore, depth, flag, bestMove)
+	t.engine.tt.Store(hash, scoreToTT(bestScore, ply), depth, flag, bestMove)
 
 	return bestScore
 }
 
+// nullMoveSearch lets the side to move pass and searches the resulting position
+// with reduced depth. If the opponent still cannot get below beta, the node
+// fails high and the score is returned with ok set. The reduction grows with
+// depth and with how far the static evaluation is above beta. At high depth
+// the cutoff is verified by a reduced search without null moves, which guards
+// against zugzwang positions.
+func (t *searchThread) nullMoveSearch(
+	ctx context.Context,
+	b *board.Board,
+	depth, beta, ply int,
+	tm *timeManager,
+) (int, bool) {
+	eval := t.evaluator.Evaluate(b)
+	if eval < beta {
+		return 0, false
+	}
+
+	r := NullMoveBaseReduction + depth/NullMoveDepthDivisor +
+		min((eval-beta)/NullMoveEvalDivisor, NullMoveMaxEvalReduction)
+
+	b.MakeNullMove()
+	t.evaluator.AddNullAccumulation()
+	t.moveStack[ply] = move.NoMove
+
+	score := -t.alphaBeta(ctx, b, depth-1-r, -beta, -beta+1, ply+1, tm)
+
+	t.evaluator.PopAccumulation()
+	b.UnmakeMove()
+
+	if ctx.Err() != nil || tm.IsDone() || score < beta {
+		return 0, false
+	}
+
+	// Mates found after passing are not proven, so don't return them
+	if score >= MateDepth {
+		score = beta
+	}
+
+	if depth < NullMoveVerificationDepth {
+		return score, true
+	}
+
+	// Verification search: null moves stay disabled for the first part of it
+	prevMinPly := t.nullMoveMinPly
+	t.nullMoveMinPly = ply + 3*(depth-r)/4
+	verified := t.alphaBeta(ctx, b, depth-r, beta-1, beta, ply, tm)
+	t.nullMoveMinPly = prevMinPly
+
+	if verified >= beta {
+		return score, true
+	}
+	return 0, false
+}
+
 // quiescence performs capture-only search to reach quiet position
-func (e *Engine) quiescence(
+func (t *searchThread) quiescence(
 	ctx context.Context,
 	b *board.Board,
 	alpha, beta, ply int,
 	tm *timeManager,
 ) int {
-	e.nodes++
+	t.pv.clear(ply)
+	t.nodes.Add(1)
 
-	if (e.nodes & 4095) == 0 {
+	if (t.nodes.Load() & 4095) == 0 {
 		if ctx.Err() != nil || tm.IsDone() {
 			// Use same logic as alpha-beta for consistency
-			if e.nodes&1 == 0 {
+			if t.nodes.Load()&1 == 0 {
 				return -Infinity
 			}
 			return Infinity
@@ -409,36 +675,43 @@ func (e *Engine) quiescence(
 
 	// Check for terminal positions
 	if b.IsCheckmate() {
-		return -MateScore + int(e.nodes) // Prefer shorter mates
+		return lossIn(ply) // Prefer shorter mates
+	}
+
+	// The search line can't grow past the size of the per-ply tables
+	if ply >= MaxDepth {
+		return t.evaluator.Evaluate(b)
 	}
 
-	if b.IsStalemate() || b.IsInsufficientMaterial() {
+	if b.IsStalemate() || b.IsInsufficientMaterial() ||
+		b.IsRepetition() || b.IsFiftyMoveDraw() {
 		return 0
 	}
 
 	// Stand-pat score
-	score := e.evaluator.Evaluate(b)
+	score := t.evaluator.Evaluate(b)
 	if score >= beta {
 		return beta
 	}
 
 	alpha = max(alpha, score)
 
-	// Generate captures
-	moves := b.GenerateCaptures()
-	moves = e.orderMoves(moves, b, move.NoMove, ply) // Order captures
+	// Captures losing material can't raise the stand-pat score, so the picker
+	// leaves them out
+	mp := &t.pickers[ply]
+	mp.InitQuiescence(b)
 
-	for _, mv := range moves {
-		copyB := b.CopyBoard()
-		if !copyB.MakeMove(mv, board.OnlyCaptures) {
+	for mv := mp.Next(); mv != move.NoMove; mv = mp.Next() {
+		if !b.MakeMove(mv, board.OnlyCaptures) {
 			continue
 		}
 
-		e.evaluator.ProcessMove(&copyB, mv)
+		t.evaluator.ProcessMove(b, mv)
 
-		score := -e.quiescence(ctx, &copyB, -beta, -alpha, ply+1, tm)
+		score := -t.quiescence(ctx, b, -beta, -alpha, ply+1, tm)
 
-		e.evaluator.PopAccumulation()
+		t.evaluator.PopAccumulation()
+		b.UnmakeMove()
 
 		if ctx.Err() != nil || tm.IsDone() {
 			if ply&1 == 0 {
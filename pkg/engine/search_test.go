@@ -0,0 +1,247 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// The tests load no network, so every evaluation is 0 and only mates and
+// draws give scores. That keeps the results exact.
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+// backRankMate is mated by Ra8 only
+const backRankMate = "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"
+
+func mustParseFEN(t *testing.T, fen string) board.Board {
+	t.Helper()
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		t.Fatalf("ParseFEN(%q) failed: %v", fen, err)
+	}
+	return b
+}
+
+// newTestThread returns the main thread of a new engine, ready to search b
+// without limits.
+func newTestThread(t *testing.T, b *board.Board) (*searchThread, *timeManager) {
+	t.Helper()
+	e := NewEngine(NewOptions())
+	e.prepareThreads()
+	thread := e.threads[0]
+	thread.evaluator.Reset(b)
+
+	tm := newTimeManager(context.Background(), time.Now(), LimitsType{}, b, 0, nil)
+	t.Cleanup(tm.Close)
+	return thread, tm
+}
+
+// TestNullMovePawnEnding checks that null moves are not tried by a side with
+// only pawns left. Black is in zugzwang after the quiet Rg6 and any move of
+// the king or the pawn is mated, but passing would look safe, so with null
+// moves allowed the mate is missed and Rxa7 is played instead.
+func TestNullMovePawnEnding(t *testing.T) {
+	b := mustParseFEN(t, "5K1k/p5R1/8/8/8/8/8/8 w - - 0 1")
+
+	e := NewEngine(NewOptions())
+	info := e.Search(context.Background(), SearchParams{
+		Boards: []board.Board{b},
+		Limits: LimitsType{Depth: 6},
+	})
+
+	if len(info.MainLine) == 0 || info.MainLine[0].String() != "g7g6" {
+		t.Errorf("best line = %v, want g7g6 first", info.MainLine)
+	}
+	if info.Score.Mate != 3 {
+		t.Errorf("score = %+v, want mate in 3", info.Score)
+	}
+}
+
+// TestNullMoveVerification checks that null moves are verified above the
+// verification depth. Black with a bishop is in zugzwang: passing holds, but
+// every move is mated. Below the verification depth the null move is trusted
+// and fails high, above it the verification search finds the mate and
+// refutes it.
+func TestNullMoveVerification(t *testing.T) {
+	tests := []struct {
+		depth  int
+		cutoff bool
+	}{
+		{NullMoveVerificationDepth - 1, true},
+		{NullMoveVerificationDepth, false},
+	}
+
+	for _, tt := range tests {
+		b := mustParseFEN(t, "3b3k/6R1/6K1/8/8/8/8/8 b - - 0 1")
+
+		thread, tm := newTestThread(t, &b)
+
+		_, cutoff := thread.nullMoveSearch(context.Background(), &b, tt.depth, 0, 0, tm)
+
+		if cutoff != tt.cutoff {
+			t.Errorf("null move at depth %d cut off = %v, want %v", tt.depth, cutoff, tt.cutoff)
+		}
+	}
+}
+
+// TestAspirationSearch checks that scores outside the aspiration window are
+// searched again with a wider window until they match the score of a search
+// with the full window.
+func TestAspirationSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		fen       string
+		prevScore int
+		move      string
+	}{
+		// The level position fails high above the window around -500 and
+		// low below the one around 500
+		{"Fail high", constants.StartPosition, -500, ""},
+		{"Fail high to mate", backRankMate, 0, "a1a8"},
+		{"Fail low", constants.StartPosition, 500, ""},
+		{"Fail low far", constants.StartPosition, 20_000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := mustParseFEN(t, tt.fen)
+			thread, tm := newTestThread(t, &b)
+			want, _ := thread.searchRoot(context.Background(), &b, AspirationMinDepth, -Infinity, Infinity, tm)
+
+			thread, tm = newTestThread(t, &b)
+			score, mv := thread.aspirationSearch(context.Background(), &b, AspirationMinDepth, tt.prevScore, tm)
+			if score != want || mv == move.NoMove || (tt.move != "" && mv.String() != tt.move) {
+				t.Errorf("aspirationSearch = %d %v, want %d %v", score, mv, want, tt.move)
+			}
+		})
+	}
+}
+
+// TestLazySMP checks that helper threads find the results of the main thread
+// and share them through the transposition table, and that a search with
+// helpers reports the result of a single thread.
+func TestLazySMP(t *testing.T) {
+	b := mustParseFEN(t, backRankMate)
+
+	options := NewOptions()
+	options.Threads = 2
+	e := NewEngine(options)
+	e.prepareThreads()
+
+	// A helper on its own, which starts one ply deeper
+	helper := e.threads[1]
+	helper.evaluator.Reset(&b)
+	tm := newTimeManager(context.Background(), time.Now(), LimitsType{Depth: 4}, &b, 0, nil)
+	defer tm.Close()
+
+	if mv := helper.iterativeDeepening(context.Background(), &b, tm); mv.String() != "a1a8" {
+		t.Errorf("helper found %v, want a1a8", mv)
+	}
+	if helper.nodes.Load() == 0 || e.threads[0].nodes.Load() != 0 {
+		t.Errorf("nodes of main and helper = %d, %d, want only the helper's",
+			e.threads[0].nodes.Load(), helper.nodes.Load())
+	}
+
+	// The main thread sees the root entry the helper stored
+	entry, ok := e.tt.Probe(b.Hash())
+	if !ok || entry.BestMove != toTTMove(helper.pv.line()[0]) || scoreFromTT(entry.Score, 0) != winIn(1) {
+		t.Errorf("root entry = %+v, %v, want the mate found by the helper", entry, ok)
+	}
+
+	for _, threads := range []int{1, 4} {
+		e.Options.Threads = threads
+		e.Clear()
+		info := e.Search(context.Background(), SearchParams{
+			Boards: []board.Board{b},
+			Limits: LimitsType{Depth: 5},
+		})
+		if len(e.threads) != threads {
+			t.Errorf("searched with %d threads, want %d", len(e.threads), threads)
+		}
+		if info.Score.Mate != 1 || len(info.MainLine) == 0 || info.MainLine[0].String() != "a1a8" {
+			t.Errorf("%d threads: score %+v, line %v, want mate 1 with a1a8", threads, info.Score, info.MainLine)
+		}
+	}
+}
+
+// TestMultiPV checks that every line of a MultiPV search starts with another
+// root move, best first, and that there are no more lines than root moves.
+func TestMultiPV(t *testing.T) {
+	tests := []struct {
+		name    string
+		fen     string
+		multiPV int
+		lines   int
+		best    string
+	}{
+		{"Mate first", backRankMate, 3, 3, "a1a8"},
+		// The king has g8, g7 and h7 only
+		{"Fewer root moves", "7k/8/8/8/8/8/8/K7 b - - 0 1", 5, 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := mustParseFEN(t, tt.fen)
+
+			options := NewOptions()
+			options.MultiPV = tt.multiPV
+			e := NewEngine(options)
+
+			// The lines of the last iteration, by rank
+			var lines []SearchInfo
+			e.Search(context.Background(), SearchParams{
+				Boards: []board.Board{b},
+				Limits: LimitsType{Depth: 4},
+				Progress: func(info SearchInfo) {
+					if info.MultiPV == 1 {
+						lines = lines[:0]
+					}
+					lines = append(lines, info)
+				},
+			})
+
+			if len(lines) != tt.lines {
+				t.Fatalf("got %d lines, want %d", len(lines), tt.lines)
+			}
+			seen := map[string]bool{}
+			for i, line := range lines {
+				if line.MultiPV != i+1 || line.Depth != lines[0].Depth || len(line.MainLine) == 0 {
+					t.Fatalf("line %d = %+v", i+1, line)
+				}
+				root := line.MainLine[0].String()
+				if seen[root] {
+					t.Errorf("line %d starts with %v again", i+1, root)
+				}
+				seen[root] = true
+				if i > 0 && line.Score.Centipawns > lines[i-1].Score.Centipawns {
+					t.Errorf("line %d scores %d, more than line %d", i+1, line.Score.Centipawns, i)
+				}
+			}
+			if tt.best != "" && (lines[0].MainLine[0].String() != tt.best || lines[0].Score.Mate != 1) {
+				t.Errorf("best line = %v, mate %d, want %v, mate 1", lines[0].MainLine, lines[0].Score.Mate, tt.best)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/color"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/syzygy"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+// applyTablebaseOptions opens the tablebases when SyzygyPath changed. Like a
+// book, tablebases that fail to open are reported once and not used.
+func (e *Engine) applyTablebaseOptions() {
+	if e.Options.SyzygyPath == e.tbPath {
+		return
+	}
+
+	e.tb, e.tbPath = nil, e.Options.SyzygyPath
+	if e.tbPath == "" {
+		return
+	}
+
+	dirs := filepath.SplitList(e.tbPath)
+	for i, dir := range dirs {
+		dirs[i] = util.MapPath(dir)
+	}
+	tb, err := syzygy.Open(strings.Join(dirs, string(filepath.ListSeparator)))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	e.tb = tb
+}
+
+// rankRootMoves restricts the root moves to the best ones of the tablebases
+// when the root position is in them. With DTZ tables the moves that keep the
+// result and make the fastest progress are left, so the search can't spoil a
+// won ending, and probing during the search is turned off as it adds nothing.
+// With WDL tables only, the search keeps probing when the root is won.
+func (e *Engine) rankRootMoves(b *board.Board) {
+	e.tbRootMoves = nil
+	e.tbProbeLimit = 0
+	if e.tb == nil {
+		return
+	}
+	e.tbProbeLimit = min(e.Options.SyzygyProbeLimit, e.tb.MaxPieces())
+	if b.Occupancies[color.BOTH].Count() > e.tbProbeLimit {
+		return
+	}
+
+	dtz := true
+	moves, ok := e.tb.RankDTZ(b)
+	if !ok {
+		dtz = false
+		moves, ok = e.tb.RankWDL(b)
+	}
+	if !ok || len(moves) == 0 {
+		return
+	}
+	e.threads[0].tbHits.Add(int64(len(moves)))
+
+	for _, m := range moves {
+		if m.Rank != moves[0].Rank {
+			break
+		}
+		e.tbRootMoves = append(e.tbRootMoves, m.Move)
+	}
+	e.tbRootScore, _ = tbScore(moves[0].WDL(), 0)
+	if dtz || moves[0].Rank <= 0 {
+		e.tbProbeLimit = 0
+	}
+}
+
+// isRootMove reports whether the root move is searched, which is the case for
+// every move unless the tablebases left out the ones spoiling the result.
+func (e *Engine) isRootMove(mv move.Move) bool {
+	return e.tbRootMoves == nil || slices.Contains(e.tbRootMoves, mv)
+}
+
+// probeWDL probes the tablebases during the search. Only positions right after
+// a capture or pawn move are probed, since the tables don't know the
+// fifty-move counter, and only without castling rights.
+func (t *searchThread) probeWDL(b *board.Board) (syzygy.WDL, bool) {
+	e := t.engine
+	if e.tbProbeLimit == 0 || b.HalfMoveClock != 0 || b.Castlings != 0 ||
+		b.Occupancies[color.BOTH].Count() > e.tbProbeLimit {
+		return syzygy.Draw, false
+	}
+
+	wdl, ok := e.tb.ProbeWDL(b)
+	if ok {
+		t.tbHits.Add(1)
+	}
+	return wdl, ok
+}
+
+// tbScore converts a tablebase result into a score and the bound it is. Wins
+// score below mates and only bound the score from below, since a mate might
+// be found in the search, and losses likewise from above. Cursed wins and
+// blessed losses are draws, scored a little off zero.
+func tbScore(wdl syzygy.WDL, ply int) (int, TTFlag) {
+	switch wdl {
+	case syzygy.Win:
+		return TBWin - ply, TTBeta
+	case syzygy.Loss:
+		return -TBWin + ply, TTAlpha
+	}
+	return int(wdl), TTExact // CursedWin and BlessedLoss are 1 and -1
+}
+
+// tbHits returns the number of tablebase hits of all threads.
+func (e *Engine) tbHits() int64 {
+	var total int64
+	for _, t := range e.threads {
+		total += t.tbHits.Load()
+	}
+	return total
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/board"
+)
+
+// krvkTable is a KRvK WDL table with a single value for each side to move,
+// won with white to move and lost with black to move
+var krvkTable = []byte{
+	0x71, 0xE8, 0x23, 0x5D, // Magic
+	0x01, 0x00, 0x66, 0x44, 0xEE, 0x00, // Split, the order and the pieces
+	0x80, 0x04, 0x80, 0x00, // Single values of Win and Loss
+}
+
+// krvkDTZTable is the KRvK DTZ table of krvkTable, with white to move and a
+// single DTZ of 9 plies
+var krvkDTZTable = []byte{
+	0xD7, 0x66, 0x0C, 0xA5, // Magic
+	0x01, 0x00, 0x66, 0x44, 0xEE, 0x00, // Split, the order and the pieces
+	0x80, 0x04, // Single value of 4 moves
+}
+
+// TestRankRootMoves checks that the moves giving away a won KRvK ending are
+// left out of the root moves.
+func TestRankRootMoves(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "KRvK.rtbw"), krvkTable, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The king on f2 attacks the rook and the squares next to it
+	b := mustParseFEN(t, "K7/8/8/8/8/8/5k2/6R1 w - - 0 1")
+
+	e := NewEngine(NewOptions())
+	e.Options.SyzygyPath = dir
+	e.applyTablebaseOptions()
+	e.prepareThreads()
+	e.rankRootMoves(&b)
+
+	var moves []string
+	for _, mv := range e.tbRootMoves {
+		moves = append(moves, mv.String())
+	}
+	slices.Sort(moves)
+	want := []string{"g1a1", "g1b1", "g1c1", "g1d1", "g1g4", "g1g5", "g1g6", "g1g7", "g1g8", "g1h1"}
+	if !slices.Equal(moves, want) {
+		t.Errorf("root moves = %v, want %v", moves, want)
+	}
+	// Without DTZ tables the won root keeps probing in the search
+	if e.tbProbeLimit != 3 {
+		t.Errorf("probe limit = %d, want 3", e.tbProbeLimit)
+	}
+	if e.tbHits() == 0 {
+		t.Error("ranking the root moves counted no tablebase hits")
+	}
+
+	// Every move of the kings draws, so all are searched without probing
+	b = mustParseFEN(t, "K7/8/8/8/8/8/5k2/8 w - - 0 1")
+	e.rankRootMoves(&b)
+	if len(e.tbRootMoves) != 3 || e.tbProbeLimit != 0 {
+		t.Errorf("root moves = %v, probe limit %d in a draw, want all 3 moves without probing",
+			e.tbRootMoves, e.tbProbeLimit)
+	}
+}
+
+// TestTablebaseRoot checks searches of roots in the tablebases. With the
+// fifty-move counter this high only the mate still wins, so the DTZ tables
+// leave one root move, which must still be searched to find the mate. Without
+// a mate the tablebase result is reported.
+func TestTablebaseRoot(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{"KRvK.rtbw": krvkTable, "KRvK.rtbz": krvkDTZTable} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := NewOptions()
+	options.SyzygyPath = dir
+	e := NewEngine(options)
+
+	b := mustParseFEN(t, "6k1/8/6K1/8/8/8/8/R7 w - - 97 80")
+	info := e.Search(context.Background(), SearchParams{
+		Boards: []board.Board{b},
+		Limits: LimitsType{Depth: 3},
+	})
+	if len(e.tbRootMoves) != 1 || e.tbRootMoves[0].String() != "a1a8" {
+		t.Fatalf("root moves = %v, want a1a8 only", e.tbRootMoves)
+	}
+	if info.Nodes == 0 || info.Score.Mate != 1 || len(info.MainLine) == 0 || info.MainLine[0].String() != "a1a8" {
+		t.Errorf("search = %d nodes, score %+v, line %v, want a1a8 searched to mate 1",
+			info.Nodes, info.Score, info.MainLine)
+	}
+
+	b = mustParseFEN(t, "K7/8/8/8/8/8/5k2/6R1 w - - 0 1")
+	info = e.Search(context.Background(), SearchParams{
+		Boards: []board.Board{b},
+		Limits: LimitsType{Depth: 2},
+	})
+	if info.Score.Centipawns != TBWin || info.Score.Mate != 0 {
+		t.Errorf("score = %+v, want the tablebase win %d", info.Score, TBWin)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"sync/atomic"
+
+	"github.com/Tecu23/argov2/internal/history"
+	"github.com/Tecu23/argov2/pkg/move"
+	"github.com/Tecu23/argov2/pkg/nnue"
+)
+
+// searchThread keeps the state owned by a single search goroutine. With Lazy
+// SMP all threads search the same root position and share nothing but the
+// transposition table, so everything updated during the search lives here.
+// Thread 0 is the main thread, the others are helpers.
+type searchThread struct {
+	engine         *Engine
+	id             int
+	nodes          atomic.Int64 // Read by the main thread while helpers are searching
+	tbHits         atomic.Int64
+	evaluator      nnue.Evaluator
+	historyTable   *history.HistoryTable
+	killerMoves    [MaxDepth][MaxKillers]move.Move
+	counterMoves   [12][64]move.Move       // Quiet refutation by piece and target square of the move before
+	moveStack      [MaxDepth + 1]move.Move // Move played at each ply of the current line
+	pickers        [MaxDepth + 1]move.MovePicker
+	pv             pvTable
+	nullMoveMinPly int         // Null moves are disabled below this ply during verification
+	skipMoves      []move.Move // Root moves left out while searching further MultiPV lines
+}
+
+func newSearchThread(e *Engine, id int) *searchThread {
+	return &searchThread{
+		engine:       e,
+		id:           id,
+		evaluator:    *nnue.NewEvaluator(),
+		historyTable: history.New(),
+	}
+}
+
+// isMain reports whether this is the thread driving the search.
+func (t *searchThread) isMain() bool {
+	return t.id == 0
+}
+
+// clear resets the per-search state of the thread.
+func (t *searchThread) clear() {
+	t.nodes.Store(0)
+	t.tbHits.Store(0)
+	t.historyTable.Clear()
+	t.killerMoves = [MaxDepth][MaxKillers]move.Move{}
+	t.counterMoves = [12][64]move.Move{}
+	t.nullMoveMinPly = 0
+}
+
+// prepareThreads brings the number of search threads in line with the Threads
+// option and resets them for a new search. Threads are kept between searches
+// so their tables don't have to be allocated again.
+func (e *Engine) prepareThreads() {
+	n := min(max(e.Options.Threads, 1), MaxThreads)
+
+	if len(e.threads) > n {
+		e.threads = e.threads[:n]
+	}
+	for len(e.threads) < n {
+		e.threads = append(e.threads, newSearchThread(e, len(e.threads)))
+	}
+
+	for _, t := range e.threads {
+		t.clear()
+	}
+}
+
+// nodes returns the number of nodes searched by all threads.
+func (e *Engine) nodes() int64 {
+	var total int64
+	for _, t := range e.threads {
+		total += t.nodes.Load()
+	}
+	return total
+}
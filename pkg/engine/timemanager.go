@@ -29,6 +29,7 @@ type timeManager struct {
 	start        time.Time          // The moment the search started
 	limits       LimitsType         // UCI-style time control limits (depth, nodes, movetime, etc..)
 	side         bool               // Side to move: true = White, false = Black
+	moveOverhead time.Duration      // Time deducted from the clock for communication delays
 	difficulty   float64            // A scaling factor  influencing time usage (adjusted based on search results)
 	lastScore    int                // The best score from the previous iteration
 	lastBestMove move.Move          // the best move found in the previous iteration
@@ -38,17 +39,21 @@ type timeManager struct {
 
 // newTimeManager creates and initializes a timeManager instance. It sets up a context with appropriate
 // deadlines or cancellations based on the provided LimisType and the current game situation.
+// When pondering, the clock only starts once ponderhit is closed.
 func newTimeManager(
 	ctx context.Context,
 	start time.Time,
 	limits LimitsType,
 	b *board.Board,
+	moveOverhead time.Duration,
+	ponderhit <-chan struct{},
 ) *timeManager {
 	tm := &timeManager{
-		start:      start,
-		limits:     limits,
-		side:       b.SideToMove == color.WHITE,
-		difficulty: 1, // Start with a neutral difficulty factor
+		start:        start,
+		limits:       limits,
+		side:         b.SideToMove == color.WHITE,
+		moveOverhead: moveOverhead,
+		difficulty:   1, // Start with a neutral difficulty factor
 	}
 
 	var cancel context.CancelFunc
@@ -56,16 +61,8 @@ func newTimeManager(
 	// If a MoveTime or classical clock times (WhiteTime/BlackTime) are set,
 	// determine the maximum allowed time for this move.
 	// Otherwise, we just allow the infinite search untill stopped manually or by conditions.
-	if limits.MoveTime > 0 || limits.WhiteTime > 0 || limits.BlackTime > 0 {
-		var maximum time.Duration
-		if limits.MoveTime > 0 {
-			// If MoveTime is specified, use it directly as the max time.
-			maximum = time.Duration(limits.MoveTime) * time.Millisecond
-		} else {
-			// Otherwise, calculate a time limit based on difficulty and maximum branch factor.
-			maximum = tm.calculateTimeLimit(maxDifficulty, maxBranchFactor)
-		}
-
+	maximum, timed := tm.maximumTime()
+	if timed && !limits.Ponder {
 		// Create a context that will expire once we reach the computed maximum time.
 		ctx, cancel = context.WithDeadline(ctx, start.Add(maximum))
 	} else {
@@ -76,9 +73,53 @@ func newTimeManager(
 	// Store the done channel and the cancel; function to use later
 	tm.done = ctx.Done()
 	tm.cancel = cancel
+
+	if limits.Ponder && timed {
+		go tm.waitForPonderHit(ctx, ponderhit, maximum)
+	}
 	return tm
 }
 
+// maximumTime returns the longest time the search may take for this move and
+// whether the limits restrict the time at all.
+func (tm *timeManager) maximumTime() (time.Duration, bool) {
+	if tm.limits.MoveTime > 0 {
+		// If MoveTime is specified, use it directly as the max time.
+		return time.Duration(tm.limits.MoveTime) * time.Millisecond, true
+	}
+
+	if tm.limits.WhiteTime > 0 || tm.limits.BlackTime > 0 {
+		// Otherwise, calculate a time limit based on difficulty and maximum branch factor.
+		return tm.calculateTimeLimit(maxDifficulty, maxBranchFactor), true
+	}
+
+	return 0, false
+}
+
+// waitForPonderHit lets a ponder search run without a clock until the GUI
+// reports that the opponent played the expected move. From then on the search
+// gets the usual maximum time, counted from the ponderhit, without restarting.
+func (tm *timeManager) waitForPonderHit(
+	ctx context.Context,
+	ponderhit <-chan struct{},
+	maximum time.Duration,
+) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-ponderhit:
+	}
+
+	timer := time.NewTimer(maximum)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+		tm.cancel()
+	}
+}
+
 // IsDone check if the time manager's context is already signaled as done (i.e., time is up or canceled)
 func (tm *timeManager) IsDone() bool {
 	select {
@@ -160,9 +201,8 @@ func (tm *timeManager) Close() {
 // branch factor, and the known time control parameters (WhiteTime, BlackTime, increments, etc..)
 func (tm *timeManager) calculateTimeLimit(difficulty, branchFactor float64) time.Duration {
 	const (
-		DefaultMovesToGo = 40                     // Assume 40 moves remain if not specified
-		MoveOverhead     = 300 * time.Millisecond // Deduct a small overhead from the total time
-		MinTimeLimit     = 1 * time.Millisecond   // Minimnum allocated time (avoid zero or negatime)
+		DefaultMovesToGo = 40                   // Assume 40 moves remain if not specified
+		MinTimeLimit     = 1 * time.Millisecond // Minimnum allocated time (avoid zero or negatime)
 		EmergencyTime    = 10 * time.Second
 		CriticalTime     = 5 * time.Second
 	)
@@ -178,7 +218,7 @@ func (tm *timeManager) calculateTimeLimit(difficulty, branchFactor float64) time
 	}
 
 	// Deduct the overhead
-	main -= MoveOverhead
+	main -= tm.moveOverhead
 	if main < MinTimeLimit {
 		main = MinTimeLimit
 	}
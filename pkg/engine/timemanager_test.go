@@ -0,0 +1,52 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/constants"
+)
+
+// TestTimeManagerPonder checks that the clock of a ponder search only starts
+// at ponderhit, and then gives the search its usual time.
+func TestTimeManagerPonder(t *testing.T) {
+	b := mustParseFEN(t, constants.StartPosition)
+	ponderhit := make(chan struct{})
+	limits := LimitsType{Ponder: true, MoveTime: 50}
+
+	tm := newTimeManager(context.Background(), time.Now(), limits, &b, 0, ponderhit)
+	defer tm.Close()
+
+	time.Sleep(150 * time.Millisecond)
+	if tm.IsDone() {
+		t.Fatal("ponder search stopped before ponderhit")
+	}
+
+	close(ponderhit)
+	hit := time.Now()
+	if tm.IsDone() {
+		t.Fatal("ponder search stopped at ponderhit")
+	}
+	select {
+	case <-tm.done:
+		if elapsed := time.Since(hit); elapsed < 50*time.Millisecond {
+			t.Errorf("search stopped %v after ponderhit, want the move time of 50ms", elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("search kept running after ponderhit")
+	}
+
+	// Without a clock the search ponders until stopped
+	limits = LimitsType{Ponder: true}
+	tm = newTimeManager(context.Background(), time.Now(), limits, &b, 0, ponderhit)
+	defer tm.Close()
+	time.Sleep(100 * time.Millisecond)
+	if tm.IsDone() {
+		t.Error("ponder search without time limits stopped")
+	}
+}
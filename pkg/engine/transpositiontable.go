@@ -4,6 +4,8 @@
 package engine
 
 import (
+	"math/bits"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/Tecu23/argov2/pkg/move"
@@ -17,65 +19,190 @@ const (
 	TTBeta                // Lower bound (failed high, score >= beta)
 )
 
-// Each position gets an entry in the table
+// TTEntry is the unpacked form of a table entry
 type TTEntry struct {
-	Key      uint64    // Zobrist hash of the position
 	Depth    int       // How Deep we searched
 	Score    int       // Position evaluation
 	Flag     TTFlag    // Type of score (exact/upper/lower bound)
-	BestMove move.Move // Best move found
+	BestMove move.Move // Best move found, without moving and captured piece (see toTTMove)
 	Age      uint8     // when this entry was created
 }
 
+/*
+	Every entry is packed into a single 64-bit word, so the table can be shared
+	by all search threads without locks: an entry is always read and written
+	as a whole. Entries are grouped into buckets of four that fill half a cache
+	line. The bucket is picked by the high bits of the hash and the low 16 bits
+	are kept in the entry to tell positions in the same bucket apart.
+
+	bits    field
+	0-15    key (low 16 bits of the hash)
+	16-31   best move (from, to and move type)
+	32-48   score + Infinity
+	49-56   depth
+	57-58   flag
+	59-63   age
+*/
+
+const (
+	ttBucketSize = 4
+
+	ttMoveShift  = 16
+	ttScoreShift = 32
+	ttDepthShift = 49
+	ttFlagShift  = 57
+	ttAgeShift   = 59
+
+	ttKeyMask   = 0xFFFF
+	ttMoveMask  = 0xFFFF
+	ttScoreMask = 0x1FFFF
+	ttDepthMask = 0xFF
+	ttFlagMask  = 0x3
+	ttAgeMask   = 0x1F
+
+	ttMoveKeyMask = move.SourceMask | move.TargetMask | move.MoveTypeMask
+)
+
+type ttBucket [ttBucketSize]atomic.Uint64
+
 type TranspositionTable struct {
-	entries []TTEntry
-	size    int
+	buckets []ttBucket
 	age     uint8
 }
 
 func NewTranspositionTable(sizeInMB int) *TranspositionTable {
-	entrySize := unsafe.Sizeof(TTEntry{})
-	entriesCount := (sizeInMB * 1024 * 1024) / int(entrySize)
-	return &TranspositionTable{
-		entries: make([]TTEntry, entriesCount),
-		size:    entriesCount,
-		age:     0,
-	}
+	tt := &TranspositionTable{}
+	tt.Resize(sizeInMB)
+	return tt
+}
+
+// Resize replaces the table with an empty one of the given size. It must not
+// be called while threads are searching.
+func (tt *TranspositionTable) Resize(sizeInMB int) {
+	bucketSize := int(unsafe.Sizeof(ttBucket{}))
+	tt.buckets = make([]ttBucket, max(sizeInMB*1024*1024/bucketSize, 1))
+	tt.age = 0
 }
 
+// NewSearch starts a new search generation. It must not be called while
+// threads are searching.
 func (tt *TranspositionTable) NewSearch() {
-	tt.age++ // Increment age for new search
+	tt.age = (tt.age + 1) & ttAgeMask // Increment age for new search
 }
 
 func (tt *TranspositionTable) Clear() {
-	for i := range tt.entries {
-		tt.entries[i] = TTEntry{}
+	for i := range tt.buckets {
+		for j := range tt.buckets[i] {
+			tt.buckets[i][j].Store(0)
+		}
 	}
 }
 
+// Store saves an entry for the position. An entry of the same position is
+// overwritten, keeping its best move when none is given. Otherwise the entry
+// replaced is the one with the least value, where shallow and old entries
+// are worth the least.
 func (tt *TranspositionTable) Store(key uint64, score, depth int, flag TTFlag, bestMove move.Move) {
-	index := key % uint64(tt.size)
-	entry := &tt.entries[index]
-
-	// Replacement strategy
-	if entry.Key == 0 || // Empty slot
-		entry.Age < tt.age || // Older entry
-		depth >= entry.Depth { // Deeper search
-		entry.Key = key
-		entry.Score = score
-		entry.Depth = depth
-		entry.Flag = flag
-		entry.BestMove = bestMove
-		entry.Age = tt.age
+	bucket := tt.bucket(key)
+	key16 := key & ttKeyMask
+
+	replace := &bucket[0]
+	replaceValue := int(^uint(0) >> 1)
+
+	for i := range bucket {
+		data := bucket[i].Load()
+
+		if data == 0 || data&ttKeyMask == key16 {
+			if bestMove == move.NoMove && data != 0 {
+				bestMove = unpackEntry(data).BestMove
+			}
+			replace = &bucket[i]
+			break
+		}
+
+		if value := tt.entryValue(data); value < replaceValue {
+			replace = &bucket[i]
+			replaceValue = value
+		}
 	}
+
+	replace.Store(packEntry(key16, score, depth, flag, bestMove, tt.age))
 }
 
 func (tt *TranspositionTable) Probe(key uint64) (TTEntry, bool) {
-	index := key % uint64(tt.size)
-	entry := tt.entries[index]
+	bucket := tt.bucket(key)
+	key16 := key & ttKeyMask
 
-	if entry.Key == key {
-		return entry, true
+	for i := range bucket {
+		if data := bucket[i].Load(); data != 0 && data&ttKeyMask == key16 {
+			return unpackEntry(data), true
+		}
 	}
 	return TTEntry{}, false
 }
+
+// HashFull returns how full the table is in permille, counting the entries
+// written during the current search in a sample of the table.
+func (tt *TranspositionTable) HashFull() int {
+	const sampleBuckets = 1000 / ttBucketSize
+
+	n := min(sampleBuckets, len(tt.buckets))
+	used := 0
+	for i := 0; i < n; i++ {
+		for j := range tt.buckets[i] {
+			data := tt.buckets[i][j].Load()
+			if data != 0 && uint8(data>>ttAgeShift) == tt.age {
+				used++
+			}
+		}
+	}
+	return used * 1000 / (n * ttBucketSize)
+}
+
+// bucket returns the bucket of a position. The high bits of the hash pick the
+// bucket, which works for any number of buckets without a modulo.
+func (tt *TranspositionTable) bucket(key uint64) *ttBucket {
+	index, _ := bits.Mul64(key, uint64(len(tt.buckets)))
+	return &tt.buckets[index]
+}
+
+// entryValue rates how much an entry is worth keeping. Every search since the
+// entry was written costs as much as 8 plies of depth.
+func (tt *TranspositionTable) entryValue(data uint64) int {
+	age := (tt.age - uint8(data>>ttAgeShift)) & ttAgeMask
+	return int(data>>ttDepthShift&ttDepthMask) - 8*int(age)
+}
+
+// toTTMove returns the part of a move kept in the table. A generated move
+// matches the table move when toTTMove(mv) == entry.BestMove.
+func toTTMove(mv move.Move) move.Move {
+	return mv & ttMoveKeyMask
+}
+
+func packEntry(key16 uint64, score, depth int, flag TTFlag, bestMove move.Move, age uint8) uint64 {
+	mv := uint64(bestMove.GetSourceSquare()) |
+		uint64(bestMove.GetTargetSquare())<<6 |
+		uint64(bestMove&move.MoveTypeMask)>>move.MoveTypeShift<<12
+
+	return key16 |
+		mv<<ttMoveShift |
+		uint64(score+Infinity)&ttScoreMask<<ttScoreShift |
+		uint64(min(max(depth, 0), ttDepthMask))<<ttDepthShift |
+		uint64(flag)&ttFlagMask<<ttFlagShift |
+		uint64(age)<<ttAgeShift
+}
+
+func unpackEntry(data uint64) TTEntry {
+	mv := data >> ttMoveShift & ttMoveMask
+	bestMove := move.Move(mv&0x3F) |
+		move.Move(mv>>6&0x3F)<<move.TargetShift |
+		move.Move(mv>>12&0xF)<<move.MoveTypeShift
+
+	return TTEntry{
+		Depth:    int(data >> ttDepthShift & ttDepthMask),
+		Score:    int(data>>ttScoreShift&ttScoreMask) - Infinity,
+		Flag:     TTFlag(data >> ttFlagShift & ttFlagMask),
+		BestMove: bestMove,
+		Age:      uint8(data >> ttAgeShift),
+	}
+}
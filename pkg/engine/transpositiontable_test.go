@@ -0,0 +1,123 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package engine
+
+import (
+	"testing"
+
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+func TestTranspositionTableStoreProbe(t *testing.T) {
+	tt := NewTranspositionTable(1)
+
+	mv := move.EncodeMove(E7, D8, WP, move.QueenPromotionCapture, BR)
+	tests := []struct {
+		name  string
+		key   uint64
+		score int
+		depth int
+		flag  TTFlag
+	}{
+		{"Positive", 0x1234_5678_9ABC_DEF0, 153, 12, TTExact},
+		{"Negative", 0x0FED_CBA9_8765_4321, -2045, 3, TTAlpha},
+		{"Mate", 0x7777_0000_1111_2222, MateScore - 7, 40, TTBeta},
+		{"Mated", 0x0101_0101_0101_0101, -MateScore + 4, 1, TTExact},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tt.Store(tc.key, tc.score, tc.depth, tc.flag, mv)
+
+			entry, ok := tt.Probe(tc.key)
+			if !ok {
+				t.Fatal("Stored entry not found")
+			}
+			if entry.Score != tc.score || entry.Depth != tc.depth || entry.Flag != tc.flag {
+				t.Errorf("Probe() = %+v, want score %d depth %d flag %d", entry, tc.score, tc.depth, tc.flag)
+			}
+			if entry.BestMove != toTTMove(mv) {
+				t.Errorf("BestMove = %v, want %v", entry.BestMove, mv)
+			}
+		})
+	}
+}
+
+func TestTranspositionTableReplacement(t *testing.T) {
+	tt := NewTranspositionTable(1)
+
+	// Keys differing only in their low bits share a bucket
+	base := uint64(0xABCD_0000_0000_0000)
+	for i := uint64(1); i <= ttBucketSize; i++ {
+		tt.Store(base|i, 0, int(i)*2, TTExact, move.NoMove)
+	}
+
+	// A new position replaces the shallowest entry
+	tt.Store(base|100, 0, 5, TTExact, move.NoMove)
+	if _, ok := tt.Probe(base | 1); ok {
+		t.Error("Expected the shallowest entry to be replaced")
+	}
+	for _, key := range []uint64{base | 2, base | 3, base | 4, base | 100} {
+		if _, ok := tt.Probe(key); !ok {
+			t.Errorf("Expected entry %x to be kept", key)
+		}
+	}
+
+	// Entries of older searches go before deeper ones
+	tt.NewSearch()
+	tt.Store(base|3, 0, 1, TTAlpha, move.NoMove)
+	tt.Store(base|200, 0, 1, TTExact, move.NoMove)
+	if _, ok := tt.Probe(base | 3); !ok {
+		t.Error("Expected the entry refreshed in this search to be kept")
+	}
+	if _, ok := tt.Probe(base | 200); !ok {
+		t.Error("Expected the new entry to be stored")
+	}
+}
+
+func TestTranspositionTableKeepsMove(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	mv := move.EncodeMove(G1, F3, WN, move.Quiet, 0)
+
+	tt.Store(42, 10, 4, TTBeta, mv)
+	tt.Store(42, 5, 6, TTAlpha, move.NoMove)
+
+	entry, _ := tt.Probe(42)
+	if entry.BestMove != toTTMove(mv) {
+		t.Errorf("BestMove = %v, want %v to be kept", entry.BestMove, mv)
+	}
+}
+
+func TestHashFull(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	if full := tt.HashFull(); full != 0 {
+		t.Errorf("HashFull() = %d on an empty table", full)
+	}
+
+	for i := range tt.buckets {
+		tt.Store(uint64(i)<<48, 0, 1, TTExact, move.NoMove)
+	}
+	if full := tt.HashFull(); full < 200 || full > 300 {
+		t.Errorf("HashFull() = %d, want about 250 with one entry per bucket", full)
+	}
+
+	tt.NewSearch()
+	if full := tt.HashFull(); full != 0 {
+		t.Errorf("HashFull() = %d, entries of an old search should not count", full)
+	}
+}
+
+func TestTranspositionTableResize(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	tt.Store(42, 10, 4, TTExact, move.NoMove)
+
+	tt.Resize(2)
+	if len(tt.buckets) != 2*1024*1024/32 {
+		t.Errorf("Got %d buckets after resizing to 2 MB", len(tt.buckets))
+	}
+	if _, ok := tt.Probe(42); ok {
+		t.Error("Expected a resized table to be empty")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+// Package epd reads positions in Extended Position Description format. Every
+// line holds the first four FEN fields followed by operations, such as
+// `bm Nf3; id "test 1";`. Perft suites putting the full FEN before the
+// operations, as in `... w KQkq - 0 1 ;D1 20 ;D2 400`, are read as well.
+//
+// Test suites such as WAC, STS or ECM are run against an engine with Run,
+// which checks the bm, am and dm operations of every position.
+package epd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Tecu23/argov2/pkg/board"
+)
+
+// Operation is a single EPD operation: an opcode followed by its operands.
+// Quoted operands are stored without the quotes.
+type Operation struct {
+	Opcode   string
+	Operands []string
+}
+
+// Position is a parsed EPD line.
+type Position struct {
+	Board      board.Board
+	Operations []Operation
+	Line       int // Line number in the input, starting at 1
+}
+
+// Operation returns the operands of the first operation with the given opcode.
+func (p *Position) Operation(opcode string) ([]string, bool) {
+	for _, op := range p.Operations {
+		if op.Opcode == opcode {
+			return op.Operands, true
+		}
+	}
+	return nil, false
+}
+
+// ID returns the operand of the id operation, or an empty string when the
+// position has none.
+func (p *Position) ID() string {
+	if operands, ok := p.Operation("id"); ok && len(operands) > 0 {
+		return operands[0]
+	}
+	return ""
+}
+
+// ReadAll parses every position in r. Empty lines and lines starting with '#'
+// are skipped.
+func ReadAll(r io.Reader) ([]*Position, error) {
+	var positions []*Position
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		p, err := Parse(text)
+		if err != nil {
+			return positions, fmt.Errorf("line %d: %w", line, err)
+		}
+		p.Line = line
+		positions = append(positions, p)
+	}
+	return positions, scanner.Err()
+}
+
+// Parse parses a single EPD line. The halfmove clock and fullmove counter are
+// taken from the FEN when present and from the hmvc and fmvn operations
+// otherwise.
+func Parse(line string) (*Position, error) {
+	var fields []string
+	rest := line
+	for range 4 {
+		var field string
+		field, rest = nextField(rest)
+		if field == "" {
+			return nil, errors.New("parse epd failed: expected 4 position fields")
+		}
+		fields = append(fields, field)
+	}
+
+	// Full FENs carry the move counters before the operations
+	if clock, after := nextField(rest); isNumber(clock) {
+		if counter, afterCounter := nextField(after); isNumber(counter) {
+			fields = append(fields, clock, counter)
+			rest = afterCounter
+		}
+	}
+
+	operations, err := parseOperations(rest)
+	if err != nil {
+		return nil, err
+	}
+	p := &Position{Operations: operations}
+
+	if len(fields) == 4 {
+		clock, counter := "0", "1"
+		if operands, ok := p.Operation("hmvc"); ok && len(operands) > 0 {
+			clock = operands[0]
+		}
+		if operands, ok := p.Operation("fmvn"); ok && len(operands) > 0 {
+			counter = operands[0]
+		}
+		fields = append(fields, clock, counter)
+	}
+
+	p.Board, err = board.ParseFEN(strings.Join(fields, " "))
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// nextField returns the next field of the position part, which ends at
+// whitespace or at the semicolon starting the operations.
+func nextField(s string) (field, rest string) {
+	s = strings.TrimLeft(s, " \t")
+	end := strings.IndexAny(s, " \t;")
+	if end == -1 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// parseOperations splits the operations at the semicolons outside of quoted
+// strings and splits each operation into its opcode and operands.
+func parseOperations(s string) ([]Operation, error) {
+	var operations []Operation
+	var tokens []string
+	var token strings.Builder
+	inToken, inQuote := false, false
+
+	endToken := func() {
+		if inToken {
+			tokens = append(tokens, token.String())
+			token.Reset()
+			inToken = false
+		}
+	}
+	endOperation := func() {
+		endToken()
+		if len(tokens) > 0 {
+			operations = append(operations, Operation{Opcode: tokens[0], Operands: tokens[1:]})
+			tokens = nil
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case inQuote && r == '"':
+			inQuote = false
+			endToken()
+		case inQuote:
+			token.WriteRune(r)
+		case r == '"':
+			endToken()
+			inQuote, inToken = true, true
+		case r == ';':
+			endOperation()
+		case r == ' ' || r == '\t':
+			endToken()
+		default:
+			token.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if inQuote {
+		return nil, errors.New("parse epd failed: unterminated string")
+	}
+	endOperation()
+	return operations, nil
+}
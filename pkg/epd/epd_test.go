@@ -0,0 +1,141 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package epd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		fen        string
+		operations []Operation
+	}{
+		{
+			name: "Test Suite",
+			line: `1k1r4/pp1b1R2/3q2pp/4p3/2B5/4Q3/PPP2B2/2K5 b - - bm Qd1+; id "BK.01";`,
+			fen:  "1k1r4/pp1b1R2/3q2pp/4p3/2B5/4Q3/PPP2B2/2K5 b - - 0 1",
+			operations: []Operation{
+				{Opcode: "bm", Operands: []string{"Qd1+"}},
+				{Opcode: "id", Operands: []string{"BK.01"}},
+			},
+		},
+		{
+			name: "Perft Suite",
+			line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 ;D1 20 ;D2 400",
+			fen:  "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			operations: []Operation{
+				{Opcode: "D1", Operands: []string{"20"}},
+				{Opcode: "D2", Operands: []string{"400"}},
+			},
+		},
+		{
+			name: "Move Counters As Operations",
+			line: `4k3/8/8/8/8/8/8/4K3 w - - hmvc 12; fmvn 40; c0 "two kings; nothing else";`,
+			fen:  "4k3/8/8/8/8/8/8/4K3 w - - 12 40",
+			operations: []Operation{
+				{Opcode: "hmvc", Operands: []string{"12"}},
+				{Opcode: "fmvn", Operands: []string{"40"}},
+				{Opcode: "c0", Operands: []string{"two kings; nothing else"}},
+			},
+		},
+		{
+			name: "Several Operands",
+			line: "4k3/8/8/8/8/8/8/R3K3 w Q - am Ra8+ Kd2;",
+			fen:  "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1",
+			operations: []Operation{
+				{Opcode: "am", Operands: []string{"Ra8+", "Kd2"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(tt.line)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if got := p.Board.FEN(); strings.TrimSpace(got) != tt.fen {
+				t.Errorf("FEN = %q, want %q", got, tt.fen)
+			}
+
+			if len(p.Operations) != len(tt.operations) {
+				t.Fatalf("got %d operations, want %d", len(p.Operations), len(tt.operations))
+			}
+			for i, op := range tt.operations {
+				got := p.Operations[i]
+				if got.Opcode != op.Opcode || !slices.Equal(got.Operands, op.Operands) {
+					t.Errorf("operation %d = %v, want %v", i, got, op)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"4k3/8/8/8/8/8/8/4K3 w",
+		`4k3/8/8/8/8/8/8/4K3 w - - id "unterminated;`,
+		"4k3/8/8/8/8/8/8/4K3 x - - bm Kd2;",
+	}
+
+	for _, line := range tests {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	input := `# Bratko-Kopec
+1k1r4/pp1b1R2/3q2pp/4p3/2B5/4Q3/PPP2B2/2K5 b - - bm Qd1+; id "BK.01";
+
+3r1k2/4npp1/1ppr3p/p6P/P2PPPP1/1NR5/5K2/2R5 w - - bm d5; id "BK.02";
+`
+
+	positions, err := ReadAll(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("got %d positions, want 2", len(positions))
+	}
+
+	if id := positions[1].ID(); id != "BK.02" {
+		t.Errorf("ID = %q, want BK.02", id)
+	}
+	if positions[1].Line != 4 {
+		t.Errorf("Line = %d, want 4", positions[1].Line)
+	}
+	if bm, ok := positions[0].Operation("bm"); !ok || !slices.Equal(bm, []string{"Qd1+"}) {
+		t.Errorf("bm = %v, want [Qd1+]", bm)
+	}
+
+	_, err = ReadAll(strings.NewReader("4k3/8/8/8/8/8/8/4K3 w - - bm Kd2;\nnot a position\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %v, want one mentioning line 2", err)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package epd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// Searcher is the part of the engine needed to run a test suite.
+type Searcher interface {
+	Clear()
+	Search(ctx context.Context, searchParams SearchParams) SearchInfo
+}
+
+// Test is a position of a test suite together with what is expected from the
+// engine. A test may combine best moves (bm), moves to avoid (am) and a
+// distance to mate (dm); all of them must be met for the test to be solved.
+type Test struct {
+	*Position
+	BestMoves  []move.Move
+	AvoidMoves []move.Move
+	MateIn     int // Moves to mate from the dm operation, 0 when not given
+
+	// Points awards partial credit to moves, as in the "c0" operation of the
+	// Strategic Test Suite: c0 "f5=10, Be5+=2, Bf2=3". Tests without it score
+	// one point when solved.
+	Points map[move.Move]int
+}
+
+// NewTest reads the expectations of a position. The moves of the bm and am
+// operations are given in SAN and must be legal in the position.
+func NewTest(p *Position) (*Test, error) {
+	t := &Test{Position: p}
+
+	var err error
+	if operands, ok := p.Operation("bm"); ok {
+		if t.BestMoves, err = parseMoves(&p.Board, operands); err != nil {
+			return nil, fmt.Errorf("line %d: bm: %w", p.Line, err)
+		}
+	}
+	if operands, ok := p.Operation("am"); ok {
+		if t.AvoidMoves, err = parseMoves(&p.Board, operands); err != nil {
+			return nil, fmt.Errorf("line %d: am: %w", p.Line, err)
+		}
+	}
+	if operands, ok := p.Operation("dm"); ok && len(operands) > 0 {
+		if t.MateIn, err = strconv.Atoi(operands[0]); err != nil || t.MateIn < 1 {
+			return nil, fmt.Errorf("line %d: dm: invalid distance %q", p.Line, operands[0])
+		}
+	}
+	if operands, ok := p.Operation("c0"); ok && len(operands) > 0 {
+		t.Points = parsePoints(&p.Board, operands[0])
+	}
+
+	if len(t.BestMoves) == 0 && len(t.AvoidMoves) == 0 && t.MateIn == 0 {
+		return nil, fmt.Errorf("line %d: no bm, am or dm operation", p.Line)
+	}
+	return t, nil
+}
+
+// Solved reports whether the engine solved the test by choosing mv with the
+// given score.
+func (t *Test) Solved(mv move.Move, score UciScore) bool {
+	if len(t.BestMoves) > 0 && !slices.Contains(t.BestMoves, mv) {
+		return false
+	}
+	if slices.Contains(t.AvoidMoves, mv) {
+		return false
+	}
+	if t.MateIn > 0 && (score.Mate <= 0 || score.Mate > t.MateIn) {
+		return false
+	}
+	return true
+}
+
+// score returns the points earned by choosing mv and the most points the test
+// can give.
+func (t *Test) score(mv move.Move, solved bool) (points, maxPoints int) {
+	if len(t.Points) == 0 {
+		if solved {
+			return 1, 1
+		}
+		return 0, 1
+	}
+
+	for _, p := range t.Points {
+		maxPoints = max(maxPoints, p)
+	}
+	return t.Points[mv], maxPoints
+}
+
+// parseMoves parses moves in SAN, falling back to coordinate notation which
+// some suites use.
+func parseMoves(b *board.Board, operands []string) ([]move.Move, error) {
+	moves := make([]move.Move, 0, len(operands))
+	for _, s := range operands {
+		mv, err := b.ParseSAN(s)
+		if err != nil {
+			var ok bool
+			if mv, ok = findCoordinateMove(b, s); !ok {
+				return nil, err
+			}
+		}
+		moves = append(moves, mv)
+	}
+	return moves, nil
+}
+
+func findCoordinateMove(b *board.Board, s string) (move.Move, bool) {
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	for _, mv := range moves.Moves() {
+		if mv.String() == s {
+			return mv, true
+		}
+	}
+	return move.NoMove, false
+}
+
+// parsePoints parses a list of "move=points" pairs. Comments of other suites
+// use c0 for free text, so anything else yields no points.
+func parsePoints(b *board.Board, s string) map[move.Move]int {
+	points := make(map[move.Move]int)
+	for _, item := range strings.Split(s, ",") {
+		san, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil
+		}
+		p, err := strconv.Atoi(value)
+		if err != nil {
+			return nil
+		}
+		mv, err := b.ParseSAN(san)
+		if err != nil {
+			return nil
+		}
+		points[mv] = p
+	}
+	return points
+}
+
+// Result is the outcome of a single test.
+type Result struct {
+	Test      *Test
+	Info      SearchInfo // Final search info of the position
+	Solved    bool
+	SolveTime time.Duration // Time from which the engine kept a solving move
+	Points    int
+	MaxPoints int
+}
+
+// BestMove returns the move chosen by the engine, or NoMove when it had none.
+func (r *Result) BestMove() move.Move {
+	if len(r.Info.MainLine) == 0 {
+		return move.NoMove
+	}
+	return r.Info.MainLine[0]
+}
+
+// Summary adds up the results of a test suite run.
+type Summary struct {
+	Tests     int
+	Solved    int
+	Points    int
+	MaxPoints int
+	Nodes     int64
+	Time      time.Duration
+	SolveTime time.Duration // Sum of the solve times of the solved tests
+}
+
+// Failed returns the number of tests that weren't solved.
+func (s Summary) Failed() int {
+	return s.Tests - s.Solved
+}
+
+// Run searches every test with the given limits, clearing the engine before
+// each one, and calls report with the result of every test as soon as it is
+// known. The run stops early when ctx is canceled.
+func Run(ctx context.Context, searcher Searcher, tests []*Test, limits LimitsType, report func(Result)) Summary {
+	var summary Summary
+
+	for _, t := range tests {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// The solve time is the start of the last streak of iterations that
+		// ended on a solving move
+		solvedAt := time.Duration(-1)
+		track := func(info SearchInfo) {
+			if len(info.MainLine) != 0 && t.Solved(info.MainLine[0], info.Score) {
+				if solvedAt < 0 {
+					solvedAt = info.Time
+				}
+			} else {
+				solvedAt = -1
+			}
+		}
+
+		searcher.Clear()
+		info := searcher.Search(ctx, SearchParams{
+			Boards:   []board.Board{t.Board},
+			Limits:   limits,
+			Progress: track,
+		})
+		track(info)
+
+		result := Result{Test: t, Info: info, Solved: solvedAt >= 0, SolveTime: max(solvedAt, 0)}
+		result.Points, result.MaxPoints = t.score(result.BestMove(), result.Solved)
+
+		summary.Tests++
+		summary.Points += result.Points
+		summary.MaxPoints += result.MaxPoints
+		summary.Nodes += info.Nodes
+		summary.Time += info.Time
+		if result.Solved {
+			summary.Solved++
+			summary.SolveTime += result.SolveTime
+		}
+
+		if report != nil {
+			report(result)
+		}
+	}
+
+	return summary
+}
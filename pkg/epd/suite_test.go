@@ -0,0 +1,162 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package epd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+func mustTest(t *testing.T, line string) *Test {
+	t.Helper()
+	p, err := Parse(line)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	test, err := NewTest(p)
+	if err != nil {
+		t.Fatalf("NewTest failed: %v", err)
+	}
+	return test
+}
+
+func TestNewTest(t *testing.T) {
+	test := mustTest(t, `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`)
+	if len(test.BestMoves) != 1 || test.BestMoves[0].String() != "g3g6" {
+		t.Errorf("BestMoves = %v, want [g3g6]", test.BestMoves)
+	}
+
+	test = mustTest(t, `4k3/8/8/8/8/8/8/R3K3 w Q - am Kd2 e1f1; dm 2;`)
+	if len(test.AvoidMoves) != 2 || test.AvoidMoves[1].String() != "e1f1" {
+		t.Errorf("AvoidMoves = %v, want [e1d2 e1f1]", test.AvoidMoves)
+	}
+	if test.MateIn != 2 {
+		t.Errorf("MateIn = %d, want 2", test.MateIn)
+	}
+
+	test = mustTest(t, `1kr5/3n4/q3p2p/p2n2p1/PppB1P2/5BP1/1P2Q2P/3R2K1 w - - bm f5; id "STS(v1.0) Undermine.001"; c0 "f5=10, Be5+=2, Bf2=3, Bg4=2";`)
+	if len(test.Points) != 4 {
+		t.Fatalf("Points = %v, want 4 moves", test.Points)
+	}
+	if points, maxPoints := test.score(test.BestMoves[0], true); points != 10 || maxPoints != 10 {
+		t.Errorf("score(f5) = %d/%d, want 10/10", points, maxPoints)
+	}
+
+	// Free text comments give no points
+	test = mustTest(t, `4k3/8/8/8/8/8/8/R3K3 w Q - bm Ra8+; c0 "the rook checks";`)
+	if test.Points != nil {
+		t.Errorf("Points = %v, want none", test.Points)
+	}
+
+	errorLines := []string{
+		`4k3/8/8/8/8/8/8/R3K3 w Q - id "nothing to test";`,
+		`4k3/8/8/8/8/8/8/R3K3 w Q - bm Qh5;`,
+		`4k3/8/8/8/8/8/8/R3K3 w Q - dm mate;`,
+	}
+	for _, line := range errorLines {
+		p, err := Parse(line)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if _, err := NewTest(p); err == nil {
+			t.Errorf("NewTest(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+func TestSolved(t *testing.T) {
+	test := mustTest(t, `6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#; am Kf1; dm 1;`)
+	ra8, kf1 := test.BestMoves[0], test.AvoidMoves[0]
+
+	tests := []struct {
+		name     string
+		move     move.Move
+		score    UciScore
+		expected bool
+	}{
+		{"Best Move With Mate", ra8, UciScore{Mate: 1}, true},
+		{"Best Move Without Mate", ra8, UciScore{Centipawns: 900}, false},
+		{"Avoided Move", kf1, UciScore{Mate: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := test.Solved(tt.move, tt.score); got != tt.expected {
+				t.Errorf("Solved = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// scriptedSearcher reports a fixed sequence of best moves, one per iteration
+type scriptedSearcher struct {
+	lines  [][]move.Move
+	clears int
+	limits LimitsType
+}
+
+func (s *scriptedSearcher) Clear() { s.clears++ }
+
+func (s *scriptedSearcher) Search(_ context.Context, params SearchParams) SearchInfo {
+	s.limits = params.Limits
+	line := s.lines[s.clears-1]
+
+	var info SearchInfo
+	for i, mv := range line {
+		info = SearchInfo{Depth: i + 1, Nodes: 100, Time: time.Duration(i+1) * time.Second, MainLine: []move.Move{mv}}
+		params.Progress(info)
+	}
+	return info
+}
+
+func TestRun(t *testing.T) {
+	first := mustTest(t, `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`)
+	second := mustTest(t, `4k3/8/8/8/8/8/8/R3K3 w Q - am Kd2; id "avoid";`)
+
+	qg6 := first.BestMoves[0]
+	other, err := first.Board.ParseSAN("Qh4")
+	if err != nil {
+		t.Fatalf("ParseSAN failed: %v", err)
+	}
+	kd2 := second.AvoidMoves[0]
+
+	searcher := &scriptedSearcher{lines: [][]move.Move{
+		{qg6, other, qg6, qg6}, // Solved from the third iteration on
+		{kd2, kd2},             // Never solved
+	}}
+
+	var results []Result
+	limits := LimitsType{MoveTime: 1000}
+	summary := Run(context.Background(), searcher, []*Test{first, second}, limits, func(r Result) {
+		results = append(results, r)
+	})
+
+	if searcher.clears != 2 || searcher.limits != limits {
+		t.Errorf("searcher cleared %d times with limits %+v", searcher.clears, searcher.limits)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Solved || results[0].SolveTime != 3*time.Second {
+		t.Errorf("first test solved %v at %v, want solved at 3s", results[0].Solved, results[0].SolveTime)
+	}
+	if results[1].Solved || results[1].BestMove() != kd2 {
+		t.Errorf("second test solved %v with %v, want failed with Kd2", results[1].Solved, results[1].BestMove())
+	}
+
+	want := Summary{
+		Tests: 2, Solved: 1, Points: 1, MaxPoints: 2,
+		Nodes: 200, Time: 6 * time.Second, SolveTime: 3 * time.Second,
+	}
+	if summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+	if summary.Failed() != 1 {
+		t.Errorf("Failed = %d, want 1", summary.Failed())
+	}
+}
@@ -2,3 +2,235 @@
 // Licensed under GNU GPL v3
 
 package move
+
+import . "github.com/Tecu23/argov2/pkg/constants"
+
+// Position is the part of a board the MovePicker works with.
+type Position interface {
+	// GenerateCaptures adds the pseudo-legal captures to the list.
+	GenerateCaptures(list *List)
+	// GenerateQuiets adds the pseudo-legal moves that aren't captures.
+	GenerateQuiets(list *List)
+	// LookupMove returns the pseudo-legal move with the source square, target
+	// square and move type of m, if there is one.
+	LookupMove(m Move) (Move, bool)
+	// SEEGreaterEqual reports whether the static exchange evaluation of the
+	// move is at least threshold.
+	SEEGreaterEqual(m Move, threshold int) bool
+}
+
+// History scores quiet moves by how often they were good before.
+type History interface {
+	Score(m Move) int
+}
+
+// pickerValues are the piece values used for MVV-LVA, indexed by piece type.
+var pickerValues = [6]int{100, 300, 300, 500, 900, 0}
+
+type stage uint8
+
+const (
+	stageTTMove stage = iota
+	stageGenerateCaptures
+	stageGoodCaptures
+	stageRefutations
+	stageGenerateQuiets
+	stageQuiets
+	stageBadCaptures
+	stageDone
+)
+
+// MovePicker hands out the moves of a position one at a time, best first,
+// in stages:
+//
+//  1. the transposition table move
+//  2. captures that don't lose material, most valuable victim first
+//  3. the killer moves and the counter-move
+//  4. quiet moves, by history score
+//  5. captures that lose material
+//
+// Moves are generated only when their stage is reached and each stage is
+// sorted lazily with selection sort, so a cutoff by an early move saves the
+// rest of the work. In quiescence mode only the captures of stage 2 are
+// returned.
+//
+// A MovePicker is big, so search code keeps one per ply and reuses it.
+type MovePicker struct {
+	pos     Position
+	history History
+
+	ttMove      Move
+	refutations [3]Move // Killer moves and counter-move
+	quiescence  bool
+
+	stage    stage
+	list     List
+	scores   [MaxMoves]int
+	index    int
+	badCount int // Captures losing material are moved to the front of list
+}
+
+// Init prepares the picker for a node of the main search. The TT move may be
+// given with only its squares and type set; killers and counter are moves the
+// position may or may not allow.
+func (p *MovePicker) Init(pos Position, history History, ttMove Move, killers [2]Move, counter Move) {
+	p.pos = pos
+	p.history = history
+	p.ttMove = ttMove
+	p.refutations = [3]Move{killers[0], killers[1], counter}
+	p.quiescence = false
+	p.stage = stageTTMove
+	p.list.Clear()
+}
+
+// InitQuiescence prepares the picker for a quiescence node, which only gets
+// the captures that don't lose material.
+func (p *MovePicker) InitQuiescence(pos Position) {
+	p.pos = pos
+	p.history = nil
+	p.ttMove = NoMove
+	p.refutations = [3]Move{}
+	p.quiescence = true
+	p.stage = stageGenerateCaptures
+	p.list.Clear()
+}
+
+// Next returns the next pseudo-legal move, or NoMove when all were returned.
+func (p *MovePicker) Next() Move {
+	for {
+		switch p.stage {
+		case stageTTMove:
+			p.stage++
+			if p.ttMove == NoMove {
+				continue
+			}
+			if mv, ok := p.pos.LookupMove(p.ttMove); ok {
+				p.ttMove = mv
+				return mv
+			}
+			p.ttMove = NoMove
+
+		case stageGenerateCaptures:
+			p.pos.GenerateCaptures(&p.list)
+			for i := range p.list.count {
+				p.scores[i] = captureScore(p.list.moves[i])
+			}
+			p.index, p.badCount = 0, 0
+			p.stage++
+
+		case stageGoodCaptures:
+			for p.index < p.list.count {
+				mv := p.pickBest(p.index)
+				p.index++
+
+				if mv == p.ttMove {
+					continue
+				}
+				if !p.pos.SEEGreaterEqual(mv, 0) {
+					// The slots before index are free, keep the move for later
+					p.list.moves[p.badCount] = mv
+					p.badCount++
+					continue
+				}
+				return mv
+			}
+
+			if p.quiescence {
+				p.stage = stageDone
+				continue
+			}
+			p.index = 0
+			p.stage++
+
+		case stageRefutations:
+			for p.index < len(p.refutations) {
+				mv := p.refutations[p.index]
+				p.index++
+
+				if p.validRefutation(mv, p.index-1) {
+					return mv
+				}
+				p.refutations[p.index-1] = NoMove
+			}
+			p.stage++
+
+		case stageGenerateQuiets:
+			p.index = p.list.count
+			p.pos.GenerateQuiets(&p.list)
+			for i := p.index; i < p.list.count; i++ {
+				p.scores[i] = p.history.Score(p.list.moves[i])
+			}
+			p.stage++
+
+		case stageQuiets:
+			for p.index < p.list.count {
+				mv := p.pickBest(p.index)
+				p.index++
+
+				if mv == p.ttMove || p.isRefutation(mv) {
+					continue
+				}
+				return mv
+			}
+			p.index = 0
+			p.stage++
+
+		case stageBadCaptures:
+			if p.index < p.badCount {
+				mv := p.list.moves[p.index]
+				p.index++
+				return mv
+			}
+			p.stage++
+
+		default:
+			return NoMove
+		}
+	}
+}
+
+// pickBest moves the best scored move from index i onward to i and returns it.
+func (p *MovePicker) pickBest(i int) Move {
+	best := i
+	for j := i + 1; j < p.list.count; j++ {
+		if p.scores[j] > p.scores[best] {
+			best = j
+		}
+	}
+
+	p.list.moves[i], p.list.moves[best] = p.list.moves[best], p.list.moves[i]
+	p.scores[i], p.scores[best] = p.scores[best], p.scores[i]
+	return p.list.moves[i]
+}
+
+// validRefutation reports whether the refutation at index i is a quiet move
+// of the position that wasn't returned before.
+func (p *MovePicker) validRefutation(mv Move, i int) bool {
+	if mv == NoMove || mv.IsCapture() || mv == p.ttMove {
+		return false
+	}
+	for _, prev := range p.refutations[:i] {
+		if mv == prev {
+			return false
+		}
+	}
+
+	found, ok := p.pos.LookupMove(mv)
+	return ok && found == mv
+}
+
+// isRefutation reports whether the move was returned as a refutation.
+func (p *MovePicker) isRefutation(mv Move) bool {
+	return mv == p.refutations[0] || mv == p.refutations[1] || mv == p.refutations[2]
+}
+
+// captureScore orders captures by the most valuable victim first and the
+// least valuable attacker among equal victims. Promotions add the value they
+// gain.
+func captureScore(m Move) int {
+	score := 8*pickerValues[m.GetCapturedPieceType()] - m.GetMovingPieceType()
+	if m.IsPromotion() {
+		score += 8 * (pickerValues[m.GetPromotionPieceType()] - pickerValues[Pawn])
+	}
+	return score
+}
@@ -2,3 +2,51 @@
 // Licensed under GNU GPL v3
 
 package move
+
+// MaxMoves is the capacity of a List. No chess position has more than 218
+// legal moves, which leaves room for the pseudo-legal ones.
+const MaxMoves = 256
+
+// List is a fixed-capacity list of moves. It lives in place (on the stack or
+// inside a longer lived struct), so generating moves into it never allocates.
+type List struct {
+	moves [MaxMoves]Move
+	count int
+}
+
+// Add appends a move to the list.
+func (l *List) Add(m Move) {
+	l.moves[l.count] = m
+	l.count++
+}
+
+// Len returns the number of moves in the list.
+func (l *List) Len() int {
+	return l.count
+}
+
+// Get returns the move at index i.
+func (l *List) Get(i int) Move {
+	return l.moves[i]
+}
+
+// Clear empties the list.
+func (l *List) Clear() {
+	l.count = 0
+}
+
+// Contains reports whether the list holds the move.
+func (l *List) Contains(m Move) bool {
+	for _, mv := range l.moves[:l.count] {
+		if mv == m {
+			return true
+		}
+	}
+	return false
+}
+
+// Moves returns the moves in the list. The slice shares the storage of the
+// list, so it's only valid until the list is changed.
+func (l *List) Moves() []Move {
+	return l.moves[:l.count]
+}
@@ -218,6 +218,8 @@ func (m Move) GetScore() int {
 
 // String returns the move in algebraic notation (e.g. "e2e4").
 // For promotions, it appends the promoted piece's letter (lowercase).
+// Chess960 castling moves target the castling rook, so they are printed in
+// king-takes-rook notation (e.g. "e1h1") as UCI_Chess960 requires.
 func (m Move) String() string {
 	if m == NoMove {
 		return "0000"
@@ -280,6 +282,9 @@ func (m Move) PrintMove() {
 // SAN returns the move in Standard Algebraic Notation (SAN).
 // It handles castling, captures, pawn moves (with promotion and en passant) as well as moves
 // by other pieces, assembling the notation string accordingly.
+//
+// Since the move carries no information about the position, the result is never
+// disambiguated and has no check or mate suffix. Use board.Board.SAN for complete SAN.
 func (m Move) SAN() string {
 	t := m.GetMoveType()
 	pcType := m.GetMovingPieceType()
@@ -312,11 +317,6 @@ func (m Move) SAN() string {
 			prefix = string(util.FileIdentifier[file])
 		}
 
-		// Append "e.p." if the move is an en passant capture.
-		if m.IsEnPassant() {
-			postfix += " e.p."
-		}
-
 		// Append promotion notation if the pawn is being promoted.
 		if m.IsPromotion() {
 			postfix += "="
@@ -387,7 +387,7 @@ func TestSANNotation(t *testing.T) {
 		{
 			name:     "En Passant",
 			move:     EncodeMove(util.FenToSq("e5"), util.FenToSq("d6"), WP, EnPassant, BP),
-			expected: "exd6 e.p.",
+			expected: "exd6",
 		},
 	}
 
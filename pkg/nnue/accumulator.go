@@ -126,11 +126,9 @@ func (a *AccumulatorTable) Use(view int, b *board.Board, evaluator *Evaluator) {
 // AddWeightsToAccumulator adds (or subtracts) network input weights to/from the accumulator.
 // The 'add' flag determines if weights are added (true) or substracted (false)
 func AddWeightsToAccumulator(add bool, idx int, src, target []int16) {
-	addWeightsToAccumulatorASM(add, src, target, InputWeights[idx][:])
+	addWeights(add, src, target, InputWeights[idx][:])
 }
 
-func addWeightsToAccumulatorASM(add bool, src, target, weights []int16)
-
 // SetUnsetPieceBothColors applies the piece move update for both White and Black perspective
 func SetUnsetPieceBothColors(input, output *Accumulator, set, unset FeatureIndex) {
 	SetUnsetPiece(input, output, White, set, unset)
@@ -143,7 +141,7 @@ func SetUnsetPiece(input, output *Accumulator, side int, set, unset FeatureIndex
 	idx1 := set.Get(side)
 	idx2 := unset.Get(side)
 
-	setUnsetPieceASM(
+	setUnset(
 		input.Summation[side][:],
 		output.Summation[side][:],
 		InputWeights[idx1][:],
@@ -151,8 +149,6 @@ func SetUnsetPiece(input, output *Accumulator, side int, set, unset FeatureIndex
 	)
 }
 
-func setUnsetPieceASM(input, output []int16, weightsSet, weightsUnset []int16)
-
 // SetUnsetUnsetPiece updates the accumulator for moves involving a piece move with an additional removal.
 // For example, when capturing, it adds the moving piece's weight, substracts the weight from the origin,
 // and substracts the captured piece's weights
@@ -161,7 +157,7 @@ func SetUnsetUnsetPiece(input, output *Accumulator, side int, set, unset1, unset
 	idx2 := unset1.Get(side)
 	idx3 := unset2.Get(side)
 
-	setUnsetUnsetPieceASM(
+	setUnsetUnset(
 		input.Summation[side][:],
 		output.Summation[side][:],
 		InputWeights[idx1][:],
@@ -170,8 +166,6 @@ func SetUnsetUnsetPiece(input, output *Accumulator, side int, set, unset1, unset
 	)
 }
 
-func setUnsetUnsetPieceASM(input, output []int16, set, unset1, unset2 []int16)
-
 // SetUnsetUnsetPieceBothColors applies the above update for both colors.
 func SetUnsetUnsetPieceBothColors(input, output *Accumulator, set, unset1, unset2 FeatureIndex) {
 	SetUnsetUnsetPiece(input, output, White, set, unset1, unset2)
@@ -1,7 +1,9 @@
 // Copyright (C) 2025 Tecu23
 // Port of Koivisto evaluation, licensed under GNU GPL v3
 
-// File: accumulator.s
+//go:build !purego
+
+// File: accumulator_amd64.s
 #include "textflag.h"
 
 // TEXT ·setUnsetPieceASM(SB),NOSPLIT,$0
@@ -10,7 +12,7 @@ TEXT ·setUnsetPieceASM(SB), NOSPLIT, $0
 	MOVQ output+24(FP), DI       // output slice data pointer
 	MOVQ weightsSet+48(FP), AX   // weights to add
 	MOVQ weightsUnset+72(FP), BX // weights to subtract
-	MOVQ input+8(FP), CX         // input slice length
+	MOVQ input_len+8(FP), CX         // input slice length
 
 	XORQ R8, R8 // index = 0
 
@@ -68,7 +70,7 @@ TEXT ·setUnsetUnsetPieceASM(SB), NOSPLIT, $0
 	MOVQ set+48(FP), AX     // weights to add
 	MOVQ unset1+72(FP), BX  // weights to subtract 1
 	MOVQ unset2+96(FP), R11 // weights to subtract 2
-	MOVQ input+8(FP), CX    // input slice length
+	MOVQ input_len+8(FP), CX    // input slice length
 
 	XORQ R8, R8 // index = 0
 
@@ -126,10 +128,10 @@ done:
 // func addWeightsToAccumulatorASM(add bool, src, target, weights []int16)
 TEXT ·addWeightsToAccumulatorASM(SB), NOSPLIT, $0
 	MOVBQZX add+0(FP), AX            // Load boolean flag 'add' into AX
-	MOVQ    src_data+8(FP), SI       // src slice data pointer
+	MOVQ    src_base+8(FP), SI       // src slice data pointer
 	MOVQ    src_len+16(FP), CX       // src slice length
-	MOVQ    target_data+32(FP), DI   // target slice data pointer
-	MOVQ    weights_data+56(FP), R10 // weights slice data pointer
+	MOVQ    target_base+32(FP), DI   // target slice data pointer
+	MOVQ    weights_base+56(FP), R10 // weights slice data pointer
 
 	XORQ R8, R8 // index = 0
 
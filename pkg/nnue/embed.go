@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+//go:build embed
+// +build embed
+
+// Package nnue keeps the NNUE (Efficiently Updated Neural Network) responsible for
+// evaluation the current position
+package nnue
+
+import (
+	"embed"
+	"fmt"
+	"log"
+)
+
+//go:embed default.net
+var embeddedWeights embed.FS
+
+// LoadDefault loads the network embedded in the binary with build tag 'embed'
+func LoadDefault() error {
+	const filename = "default.net"
+
+	// Open the embedded file
+	weightFile, err := embeddedWeights.Open(filename)
+	if err != nil {
+		return fmt.Errorf("could not open embedded weights file: %v", err)
+	}
+	defer weightFile.Close()
+
+	// Load Weights from the file
+	if err := LoadReader(weightFile); err != nil {
+		return fmt.Errorf("error loading embedded weights: %v", err)
+	}
+
+	// Standard output is the UCI channel, so the note goes to the log
+	log.Println("Loaded embedded NNUE weights")
+	return nil
+}
@@ -21,6 +21,7 @@ type Evaluator struct {
 	HistoryIndex             int               // Current index in the history stack
 	AccumulatorTable         *AccumulatorTable // Cached accumulators based on king positions
 	AccumulatorIsInitialized [2]bool           // Flags to track whether accumulators have been initialized for each color
+	network                  uint64            // Generation of the network the cached accumulators were built with
 }
 
 // NewEvaluator creates and initializes a new NNUE evaluator instance.
@@ -29,6 +30,7 @@ func NewEvaluator() *Evaluator {
 		History:          make([]Accumulator, 0, 128), // Start with an initial accumulator state
 		HistoryIndex:     0,
 		AccumulatorTable: &AccumulatorTable{}, // Create a new table for caching accumulators
+		network:          networkGeneration,
 	}
 
 	evaluator.AccumulatorTable.Reset()
@@ -37,7 +39,15 @@ func NewEvaluator() *Evaluator {
 
 // Reset reinitializes the evaluator for a new board position.
 // It resets the accumulator history and reinitializes accumulators for both colors.
+// When another network was loaded since the last reset, the cached accumulators
+// are built again from scratch.
 func (e *Evaluator) Reset(b *board.Board) {
+	if e.network != networkGeneration {
+		*e.AccumulatorTable = AccumulatorTable{}
+		e.AccumulatorTable.Reset()
+		e.network = networkGeneration
+	}
+
 	e.History = []Accumulator{{}} // Clear history to initial state
 	e.HistoryIndex = 0
 	e.ResetAccumulator(b, White)
@@ -87,7 +97,7 @@ func (e *Evaluator) eval(activePlayer int) int {
 	accActive := e.History[e.HistoryIndex].Summation[activePlayer][:]
 	accInactive := e.History[e.HistoryIndex].Summation[1-activePlayer][:]
 
-	sum := computeScoreASM(accActive, accInactive, HiddenWeights[0][:], HiddenBias[0])
+	sum := computeScore(accActive, accInactive, HiddenWeights[0][:], HiddenBias[0])
 
 	// Scale the sum based on the weight multipliers to obtain the final evaluation score
 	result := int(
@@ -97,8 +107,6 @@ func (e *Evaluator) eval(activePlayer int) int {
 	return result
 }
 
-func computeScoreASM(accActive, accInactive []int16, hiddenWeights []int16, hiddenBias int32) int32
-
 // AddNewAccumulation adds a new accumulator state to the history stack,
 // so that subsequent move updates are applied on a new state.
 func (e *Evaluator) AddNewAccumulation() {
@@ -114,6 +122,16 @@ func (e *Evaluator) AddNewAccumulation() {
 	e.AccumulatorIsInitialized[Black] = false
 }
 
+// AddNullAccumulation pushes a copy of the current accumulator state for a
+// null move. No piece changes, so the position keeps the same features, but the
+// history stack stays in step with the search and PopAccumulation undoes it.
+func (e *Evaluator) AddNullAccumulation() {
+	e.AddNewAccumulation()
+	e.History[e.HistoryIndex] = e.History[e.HistoryIndex-1]
+	e.AccumulatorIsInitialized[White] = true
+	e.AccumulatorIsInitialized[Black] = true
+}
+
 // PopAccumulation undoes the last move by moving back in the history stack.
 func (e *Evaluator) PopAccumulation() {
 	e.HistoryIndex--
@@ -175,9 +193,17 @@ func (e *Evaluator) ProcessMove(b *board.Board, m move.Move) {
 
 	isCapture := m.IsCapture()
 	isCastling := m.IsCastle()
-	isQueenCast := m.IsQueenCastle()
 	enPass := m.IsEnPassant()
 
+	// Castling moves may encode the rook square as target (Chess960), so the
+	// king and rook squares are taken from the board instead
+	var rookFrom, rookTo int
+	if isCastling {
+		_, kingTo, rookSq, rookDest := b.CastlingSquares(m)
+		to = ConvertSquare(kingTo)
+		rookFrom, rookTo = ConvertSquare(rookSq), ConvertSquare(rookDest)
+	}
+
 	capturedPiece := -1
 	if isCapture {
 		capturedPiece = m.GetCapturedPiece()
@@ -215,17 +241,6 @@ func (e *Evaluator) ProcessMove(b *board.Board, m move.Move) {
 					FeatureIndex{capt, 1 - c, to, wKingSq, bKingSq},
 				)
 			} else if isCastling {
-				val := 3
-				if isQueenCast {
-					val = -4
-				}
-				rookFrom := from + val
-				val = -1
-				if isQueenCast {
-					val = 1
-				}
-				rookTo := to + val
-
 				pc := util.GetPieceType(piece)
 
 				SetSetUnsetUnsetPieceBothColors(
@@ -259,16 +274,6 @@ func (e *Evaluator) ProcessMove(b *board.Board, m move.Move) {
 					FeatureIndex{capt, 1 - c, to, wKingSq, bKingSq},
 				)
 			} else if isCastling {
-				val := 3
-				if isQueenCast {
-					val = -4
-				}
-				rookFrom := from + val
-				val = -1
-				if isQueenCast {
-					val = 1
-				}
-				rookTo := to + val
 				pc := util.GetPieceType(piece)
 
 				SetSetUnsetUnsetPiece(
@@ -1,11 +1,13 @@
 // Copyright (C) 2025 Tecu23
 // Port of Koivisto evaluation, licensed under GNU GPL v3
 
-// File: evaluation.s
+//go:build !purego
+
+// File: evaluation_amd64.s
 #include "textflag.h"
 
 // func computeScoreASM(accActive, accInactive []int16, hiddenWeights []int16, hiddenBias int32) int32
-TEXT ·computeScoreASM(SB), NOSPLIT, $0-40
+TEXT ·computeScoreASM(SB), NOSPLIT, $0-84
 	// Input parameters:
 	// accActive     +0(FP)
 	// accActive_len +8(FP)
@@ -4,6 +4,7 @@
 package nnue
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -21,6 +22,7 @@ func init() {
 	attacks.InitPawnAttacks()
 	attacks.InitKnightAttacks()
 	attacks.InitKingAttacks()
+	attacks.InitRays()
 	attacks.InitSliderPiecesAttacks(Bishop)
 	attacks.InitSliderPiecesAttacks(Rook)
 
@@ -28,14 +30,26 @@ func init() {
 
 	hash.Init()
 
+	// Without build tag 'embed' there's no network to check the evaluations of
 	err := InitializeNNUE()
-	if err != nil {
+	if err != nil && !errors.Is(err, ErrNoNetwork) {
 		// This will cause the tests to fail immediately if weights don't load
 		panic(fmt.Sprintf("Failed to load NNUE weights: %v", err))
 	}
 }
 
+// requireNetwork skips tests of the evaluations of the embedded network when
+// it isn't built in.
+func requireNetwork(t *testing.T) {
+	t.Helper()
+	if Checksum() == 0 {
+		t.Skip("no embedded network, build with -tags embed")
+	}
+}
+
 func TestEval(t *testing.T) {
+	requireNetwork(t)
+
 	tests := []struct {
 		name string
 		fen  string
@@ -95,6 +109,8 @@ func TestEval(t *testing.T) {
 }
 
 func TestProcessMoveAndEvaluate(t *testing.T) {
+	requireNetwork(t)
+
 	testCases := []struct {
 		Name          string
 		StartFEN      string
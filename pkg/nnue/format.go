@@ -0,0 +1,289 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+// Package nnue keeps the NNUE (Efficiently Updated Neural Network) responsible for
+// evaluation the current position
+package nnue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+// Network files in the container format describe the network they hold, so a
+// file of another architecture is rejected instead of loaded as garbage. All
+// values are little endian:
+//
+//	magic              4 bytes "ARGN"
+//	version            uint32, FormatVersion
+//	input size         uint32, InputSize
+//	hidden size        uint32, HiddenSize
+//	output size        uint32, OutputSize
+//	king buckets       uint32, the number of king buckets
+//	king bucket layout 64 bytes, the bucket of every square as in KingSquareIndices
+//	input multiplier   uint32, InputWeightMultiplier
+//	hidden multiplier  uint32, HiddenWeightMultiplier
+//	description size   uint32
+//	description        UTF-8 text, such as the name and training of the network
+//	layers             the weights in the order of the legacy format
+//	hash               uint32, the CRC-32 (IEEE) of all bytes before it
+//
+// Version 1 headers end after the output size. Their networks are assumed to
+// have the king buckets and multipliers in defs.go, and no description.
+//
+// Legacy files are only the layers, and are assumed to be of the dimensions
+// in defs.go.
+
+// FormatVersion is the version of the container format written
+const FormatVersion = 2
+
+// maxDescription bounds the description, so a corrupt size can't make a
+// reader allocate much
+const maxDescription = 1 << 16
+
+var (
+	formatMagic = [4]byte{'A', 'R', 'G', 'N'}
+
+	// ErrLegacyFormat is returned by ReadHeader for files without a header
+	ErrLegacyFormat = errors.New("network in the legacy format, without header")
+)
+
+// Header describes the network in a network file.
+type Header struct {
+	Version          int // 0 for networks loaded from the legacy format
+	InputSize        int
+	HiddenSize       int
+	OutputSize       int
+	KingBuckets      int
+	KingBucketLayout [64]uint8
+	InputMultiplier  int
+	HiddenMultiplier int
+	Description      string
+}
+
+// fileHeader is the part of the header stored by every version
+type fileHeader struct {
+	Magic      [4]byte
+	Version    uint32
+	InputSize  uint32
+	HiddenSize uint32
+	OutputSize uint32
+}
+
+// fileHeaderV2 is the part of the header version 2 adds, up to the
+// description
+type fileHeaderV2 struct {
+	KingBuckets      uint32
+	KingBucketLayout [64]uint8
+	InputMultiplier  uint32
+	HiddenMultiplier uint32
+	DescriptionSize  uint32
+}
+
+// NewHeader returns the header of a network of the dimensions this build
+// evaluates with.
+func NewHeader(description string) Header {
+	h := Header{
+		Version:          FormatVersion,
+		InputSize:        InputSize,
+		HiddenSize:       HiddenSize,
+		OutputSize:       OutputSize,
+		KingBuckets:      slices.Max(KingSquareIndices[:]) + 1,
+		InputMultiplier:  InputWeightMultiplier,
+		HiddenMultiplier: HiddenWeightMultiplier,
+		Description:      description,
+	}
+	for sq, bucket := range KingSquareIndices {
+		h.KingBucketLayout[sq] = uint8(bucket)
+	}
+	return h
+}
+
+// Verify checks that the network of the header can be evaluated by this
+// build, which needs the dimensions, king buckets and multipliers of defs.go.
+// Version 1 headers only tell the dimensions.
+func (h *Header) Verify() error {
+	type check struct {
+		name      string
+		got, want int
+	}
+
+	want := NewHeader("")
+	checks := []check{
+		{"input size", h.InputSize, want.InputSize},
+		{"hidden size", h.HiddenSize, want.HiddenSize},
+		{"output size", h.OutputSize, want.OutputSize},
+	}
+	if h.Version >= 2 {
+		checks = append(checks,
+			check{"king buckets", h.KingBuckets, want.KingBuckets},
+			check{"input multiplier", h.InputMultiplier, want.InputMultiplier},
+			check{"hidden multiplier", h.HiddenMultiplier, want.HiddenMultiplier},
+		)
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			return fmt.Errorf("network %v is %d, this build needs %d", c.name, c.got, c.want)
+		}
+	}
+	if h.Version >= 2 && h.KingBucketLayout != want.KingBucketLayout {
+		return errors.New("network has another king bucket layout than this build")
+	}
+	return nil
+}
+
+// layersSize returns the size of the layers of the network of the header
+func (h *Header) layersSize() int {
+	return 2*h.InputSize*h.HiddenSize + 2*h.HiddenSize + 2*h.OutputSize*2*h.HiddenSize + 4*h.OutputSize
+}
+
+// ReadHeader reads the header of a network file, which tells what network it
+// holds without loading it. It returns ErrLegacyFormat for legacy files.
+func ReadHeader(r io.Reader) (Header, error) {
+	buf := make([]byte, binary.Size(fileHeader{}))
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return Header{}, err
+	}
+	if !bytes.HasPrefix(buf[:n], formatMagic[:]) {
+		return Header{}, ErrLegacyFormat
+	}
+	if err != nil {
+		return Header{}, fmt.Errorf("network header truncated: %w", err)
+	}
+
+	var fh fileHeader
+	binary.Read(bytes.NewReader(buf), binary.LittleEndian, &fh)
+	if fh.Version < 1 || fh.Version > FormatVersion {
+		return Header{}, fmt.Errorf("network format version %d, want 1 to %d", fh.Version, FormatVersion)
+	}
+
+	h := Header{
+		Version:    int(fh.Version),
+		InputSize:  int(fh.InputSize),
+		HiddenSize: int(fh.HiddenSize),
+		OutputSize: int(fh.OutputSize),
+	}
+	if fh.Version == 1 {
+		return h, nil
+	}
+
+	var fh2 fileHeaderV2
+	if err := binary.Read(r, binary.LittleEndian, &fh2); err != nil {
+		return Header{}, fmt.Errorf("network header truncated: %w", err)
+	}
+	if fh2.DescriptionSize > maxDescription {
+		return Header{}, fmt.Errorf("network description of %d bytes", fh2.DescriptionSize)
+	}
+
+	description := make([]byte, fh2.DescriptionSize)
+	if _, err := io.ReadFull(r, description); err != nil {
+		return Header{}, fmt.Errorf("network header truncated: %w", err)
+	}
+
+	h.KingBuckets = int(fh2.KingBuckets)
+	h.KingBucketLayout = fh2.KingBucketLayout
+	h.InputMultiplier = int(fh2.InputMultiplier)
+	h.HiddenMultiplier = int(fh2.HiddenMultiplier)
+	h.Description = string(description)
+	return h, nil
+}
+
+// parseContainer checks a network file in the container format and returns
+// its header, layers and hash.
+func parseContainer(data []byte) (Header, []byte, uint32, error) {
+	r := bytes.NewReader(data)
+	h, err := ReadHeader(r)
+	if err != nil {
+		return h, nil, 0, err
+	}
+	if err := h.Verify(); err != nil {
+		return h, nil, 0, err
+	}
+
+	layers := data[len(data)-r.Len():]
+	if len(layers) != networkSize+4 {
+		return h, nil, 0, fmt.Errorf("network layers of %d bytes, want %d", len(layers)-4, networkSize)
+	}
+	layers, trailer := layers[:networkSize], layers[networkSize:]
+
+	hash := binary.LittleEndian.Uint32(trailer)
+	if sum := crc32.ChecksumIEEE(data[:len(data)-4]); sum != hash {
+		return h, nil, 0, fmt.Errorf("network hash %08x doesn't match its data, %08x", hash, sum)
+	}
+	return h, layers, hash, nil
+}
+
+// writeContainer writes a network file with the header and layers
+func writeContainer(w io.Writer, h Header, layers []byte) error {
+	if len(h.Description) > maxDescription {
+		return fmt.Errorf("network description of %d bytes, at most %d", len(h.Description), maxDescription)
+	}
+	if len(layers) != h.layersSize() {
+		return fmt.Errorf("network layers of %d bytes, want %d", len(layers), h.layersSize())
+	}
+
+	var buf bytes.Buffer
+	fh := fileHeader{
+		Magic:      formatMagic,
+		Version:    FormatVersion,
+		InputSize:  uint32(h.InputSize),
+		HiddenSize: uint32(h.HiddenSize),
+		OutputSize: uint32(h.OutputSize),
+	}
+	fh2 := fileHeaderV2{
+		KingBuckets:      uint32(h.KingBuckets),
+		KingBucketLayout: h.KingBucketLayout,
+		InputMultiplier:  uint32(h.InputMultiplier),
+		HiddenMultiplier: uint32(h.HiddenMultiplier),
+		DescriptionSize:  uint32(len(h.Description)),
+	}
+	binary.Write(&buf, binary.LittleEndian, &fh)
+	binary.Write(&buf, binary.LittleEndian, &fh2)
+	buf.WriteString(h.Description)
+	buf.Write(layers)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(buf.Bytes())))
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ConvertLegacy reads a network in the legacy format from r and writes it to
+// w in the container format, with the dimensions of this build and the
+// description given.
+func ConvertLegacy(r io.Reader, w io.Writer, description string) error {
+	data, err := readLegacy(r)
+	if err != nil {
+		return err
+	}
+	return writeContainer(w, NewHeader(description), data)
+}
+
+// readLegacy reads the layers of a legacy network file
+func readLegacy(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, networkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, formatMagic[:]) {
+		return nil, errors.New("network already in the container format")
+	}
+	return data, checkLegacy(data)
+}
+
+// checkLegacy checks that a legacy network file is exactly the size of a
+// network of this build, the only check its format allows.
+func checkLegacy(data []byte) error {
+	if len(data) != networkSize {
+		if len(data) > networkSize {
+			return fmt.Errorf("network larger than %d bytes", networkSize)
+		}
+		return fmt.Errorf("network of %d bytes, want %d", len(data), networkSize)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+package nnue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/board"
+)
+
+// convertNetwork returns a random network in the container format
+func convertNetwork(t *testing.T, seed int64, description string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := ConvertLegacy(bytes.NewReader(randomNetwork(seed)), &buf, description); err != nil {
+		t.Fatalf("ConvertLegacy failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// rehash updates the trailing hash of a network after it was changed
+func rehash(data []byte) []byte {
+	n := len(data) - 4
+	binary.LittleEndian.PutUint32(data[n:], crc32.ChecksumIEEE(data[:n]))
+	return data
+}
+
+func TestConvertLegacy(t *testing.T) {
+	keepNetwork(t)
+
+	data := convertNetwork(t, 6, "test net, epoch 40")
+	h, err := ReadHeader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if want := NewHeader("test net, epoch 40"); h != want {
+		t.Errorf("ReadHeader = %+v, want %+v", h, want)
+	}
+	if h.KingBuckets != 16 || h.KingBucketLayout[63] != 14 {
+		t.Errorf("header has %d king buckets, h1 in bucket %d", h.KingBuckets, h.KingBucketLayout[63])
+	}
+	if err := h.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// Both formats load the same network
+	b, _ := board.ParseFEN("r1bqkbnr/ppp2ppp/2np4/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 4")
+	evaluate := func(data []byte) int {
+		t.Helper()
+		if err := LoadReader(bytes.NewReader(data)); err != nil {
+			t.Fatalf("LoadReader failed: %v", err)
+		}
+		e := NewEvaluator()
+		e.Reset(&b)
+		return e.Evaluate(&b)
+	}
+	if legacy, container := evaluate(randomNetwork(6)), evaluate(data); legacy != container {
+		t.Errorf("evaluation = %d after conversion, want %d", container, legacy)
+	}
+	if loaded := LoadedHeader(); loaded.Description != "test net, epoch 40" {
+		t.Errorf("LoadedHeader().Description = %q", loaded.Description)
+	}
+	if got, want := Checksum(), binary.LittleEndian.Uint32(data[len(data)-4:]); got != want {
+		t.Errorf("Checksum = %08x, want the trailing hash %08x", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := ConvertLegacy(bytes.NewReader(data), &buf, ""); err == nil {
+		t.Error("ConvertLegacy of a converted network succeeded, want an error")
+	}
+	if _, err := ReadHeader(bytes.NewReader(randomNetwork(6))); !errors.Is(err, ErrLegacyFormat) {
+		t.Errorf("ReadHeader of a legacy network = %v, want ErrLegacyFormat", err)
+	}
+}
+
+// TestLoadConverted checks that networks in the container format are
+// rejected when their header or hash doesn't match.
+func TestLoadConverted(t *testing.T) {
+	keepNetwork(t)
+
+	data := convertNetwork(t, 7, "")
+	if err := LoadReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	checksum := Checksum()
+
+	// Offsets into the header, after the magic
+	const (
+		version    = 4
+		hiddenSize = 12
+		layout     = 24
+	)
+	corrupt := func(offset int, value uint32) []byte {
+		bad := bytes.Clone(data)
+		binary.LittleEndian.PutUint32(bad[offset:], value)
+		return bad
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"other version", rehash(corrupt(version, FormatVersion+1))},
+		{"other hidden size", rehash(corrupt(hiddenSize, 256))},
+		{"other king buckets", rehash(corrupt(layout, 0x01010101))},
+		{"changed weight", corrupt(len(data)-100, 0x12345678)},
+		{"truncated", data[:len(data)-1]},
+		{"truncated header", data[:40]},
+		{"trailing data", append(bytes.Clone(data), 0)},
+	}
+	for _, tt := range tests {
+		if err := LoadReader(bytes.NewReader(tt.data)); err == nil {
+			t.Errorf("LoadReader of a network with %v succeeded, want an error", tt.name)
+		}
+	}
+	if Checksum() != checksum {
+		t.Error("a failed load changed the network")
+	}
+
+	h, _ := ReadHeader(bytes.NewReader(rehash(corrupt(hiddenSize, 256))))
+	if err := h.Verify(); err == nil || h.HiddenSize != 256 {
+		t.Errorf("Verify of a network with 256 hidden neurons = %v, want an error", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+// File: kernels.go
+
+package nnue
+
+// The kernels below are the portable versions of the assembly in
+// accumulator_amd64.s and evaluation_amd64.s. They're used on other
+// architectures and with build tag 'purego', and must give the same results
+// bit for bit: int16 sums wrap around like PADDW and PSUBW, and int32 sums
+// like PADDD, so the order of the additions doesn't matter.
+
+// addWeightsGo sets target to src plus weights, or src minus weights
+func addWeightsGo(add bool, src, target, weights []int16) {
+	target = target[:len(src)]
+	weights = weights[:len(src)]
+	if add {
+		for i := range src {
+			target[i] = src[i] + weights[i]
+		}
+		return
+	}
+	for i := range src {
+		target[i] = src[i] - weights[i]
+	}
+}
+
+// setUnsetGo sets output to input plus set minus unset
+func setUnsetGo(input, output, set, unset []int16) {
+	output = output[:len(input)]
+	set = set[:len(input)]
+	unset = unset[:len(input)]
+	for i := range input {
+		output[i] = input[i] + set[i] - unset[i]
+	}
+}
+
+// setUnsetUnsetGo sets output to input plus set minus unset1 and unset2
+func setUnsetUnsetGo(input, output, set, unset1, unset2 []int16) {
+	output = output[:len(input)]
+	set = set[:len(input)]
+	unset1 = unset1[:len(input)]
+	unset2 = unset2[:len(input)]
+	for i := range input {
+		output[i] = input[i] + set[i] - unset1[i] - unset2[i]
+	}
+}
+
+// computeScoreGo returns the output of the hidden layer: the bias plus the
+// clipped accumulators of both sides times their half of the weights.
+func computeScoreGo(accActive, accInactive, hiddenWeights []int16, hiddenBias int32) int32 {
+	n := len(accActive)
+	accInactive = accInactive[:n]
+	activeWeights, inactiveWeights := hiddenWeights[:n], hiddenWeights[n:2*n]
+
+	sum := hiddenBias
+	for i := range n {
+		sum += int32(max(accActive[i], 0)) * int32(activeWeights[i])
+		sum += int32(max(accInactive[i], 0)) * int32(inactiveWeights[i])
+	}
+	return sum
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+//go:build !purego
+
+// File: kernels_amd64.go
+
+package nnue
+
+// The kernels run in assembly on amd64 unless built with tag 'purego'. The
+// portable versions in kernels.go stay built, so tests can compare the two.
+
+func addWeights(add bool, src, target, weights []int16) {
+	addWeightsToAccumulatorASM(add, src, target, weights)
+}
+
+func setUnset(input, output, set, unset []int16) {
+	setUnsetPieceASM(input, output, set, unset)
+}
+
+func setUnsetUnset(input, output, set, unset1, unset2 []int16) {
+	setUnsetUnsetPieceASM(input, output, set, unset1, unset2)
+}
+
+func computeScore(accActive, accInactive, hiddenWeights []int16, hiddenBias int32) int32 {
+	return computeScoreASM(accActive, accInactive, hiddenWeights, hiddenBias)
+}
+
+//go:noescape
+func addWeightsToAccumulatorASM(add bool, src, target, weights []int16)
+
+//go:noescape
+func setUnsetPieceASM(input, output []int16, weightsSet, weightsUnset []int16)
+
+//go:noescape
+func setUnsetUnsetPieceASM(input, output []int16, set, unset1, unset2 []int16)
+
+//go:noescape
+func computeScoreASM(accActive, accInactive []int16, hiddenWeights []int16, hiddenBias int32) int32
@@ -0,0 +1,109 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+//go:build !purego
+
+package nnue
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// randomVector returns int16 values over their whole range, so the sums
+// overflow and both kernels must wrap around the same way.
+func randomVector(r *rand.Rand, n int) []int16 {
+	v := make([]int16, n)
+	for i := range v {
+		v[i] = int16(r.Uint32())
+	}
+	return v
+}
+
+// TestKernels checks that the portable kernels give the results of the
+// assembly ones bit for bit.
+func TestKernels(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for range 100 {
+		input := randomVector(r, HiddenSize)
+		set, unset1, unset2 := randomVector(r, HiddenSize), randomVector(r, HiddenSize), randomVector(r, HiddenSize)
+
+		for _, add := range []bool{true, false} {
+			want, got := make([]int16, HiddenSize), make([]int16, HiddenSize)
+			addWeightsToAccumulatorASM(add, input, want, set)
+			addWeightsGo(add, input, got, set)
+			if !slices.Equal(got, want) {
+				t.Fatalf("addWeightsGo(%v) differs from the assembly", add)
+			}
+		}
+
+		want, got := make([]int16, HiddenSize), make([]int16, HiddenSize)
+		setUnsetPieceASM(input, want, set, unset1)
+		setUnsetGo(input, got, set, unset1)
+		if !slices.Equal(got, want) {
+			t.Fatal("setUnsetGo differs from the assembly")
+		}
+
+		setUnsetUnsetPieceASM(input, want, set, unset1, unset2)
+		setUnsetUnsetGo(input, got, set, unset1, unset2)
+		if !slices.Equal(got, want) {
+			t.Fatal("setUnsetUnsetGo differs from the assembly")
+		}
+
+		// The accumulators are updated in place too
+		want, got = slices.Clone(input), slices.Clone(input)
+		setUnsetUnsetPieceASM(want, want, set, unset1, unset2)
+		setUnsetUnsetGo(got, got, set, unset1, unset2)
+		if !slices.Equal(got, want) {
+			t.Fatal("setUnsetUnsetGo in place differs from the assembly")
+		}
+
+		active, inactive := randomVector(r, HiddenSize), randomVector(r, HiddenSize)
+		weights := randomVector(r, HiddenDSize)
+		bias := int32(r.Uint32())
+		if got, want := computeScoreGo(active, inactive, weights, bias),
+			computeScoreASM(active, inactive, weights, bias); got != want {
+			t.Fatalf("computeScoreGo = %d, want %d", got, want)
+		}
+	}
+}
+
+// TestKernelScores checks that both kernels score the accumulators of an
+// evaluator the same as it follows a game.
+func TestKernelScores(t *testing.T) {
+	keepNetwork(t)
+	if err := LoadReader(bytes.NewReader(randomNetwork(5))); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := board.ParseFEN("r3k2r/ppp2ppp/2n1bn2/3pP3/3P4/2N2N2/PPP2PPP/R3K2R w KQkq d6 0 1")
+	e := NewEvaluator()
+	e.Reset(&b)
+
+	r := rand.New(rand.NewSource(2))
+	for ply := range 40 {
+		acc := &e.History[e.HistoryIndex].Summation
+		for side := range 2 {
+			got := computeScoreGo(acc[side][:], acc[1-side][:], HiddenWeights[0][:], HiddenBias[0])
+			want := computeScoreASM(acc[side][:], acc[1-side][:], HiddenWeights[0][:], HiddenBias[0])
+			if got != want {
+				t.Fatalf("ply %d, side %d: computeScoreGo = %d, want %d", ply, side, got, want)
+			}
+		}
+
+		var moves move.List
+		b.GenerateLegalMoves(&moves)
+		if moves.Len() == 0 {
+			break
+		}
+		mv := moves.Moves()[r.Intn(moves.Len())]
+		b.MakeMove(mv, board.AllMoves)
+		e.ProcessMove(&b, mv)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+//go:build !amd64 || purego
+
+// File: kernels_generic.go
+
+package nnue
+
+// The portable kernels run on architectures without assembly kernels and with
+// build tag 'purego'.
+
+func addWeights(add bool, src, target, weights []int16) {
+	addWeightsGo(add, src, target, weights)
+}
+
+func setUnset(input, output, set, unset []int16) {
+	setUnsetGo(input, output, set, unset)
+}
+
+func setUnsetUnset(input, output, set, unset1, unset2 []int16) {
+	setUnsetUnsetGo(input, output, set, unset1, unset2)
+}
+
+func computeScore(accActive, accInactive, hiddenWeights []int16, hiddenBias int32) int32 {
+	return computeScoreGo(accActive, accInactive, hiddenWeights, hiddenBias)
+}
@@ -1,102 +1,140 @@
 // Copyright (C) 2025 Tecu23
 // Port of Koivisto evaluation, licensed under GNU GPL v3
 
-//go:build embed
-// +build embed
-
 // Package nnue keeps the NNUE (Efficiently Updated Neural Network) responsible for
 // evaluation the current position
 package nnue
 
 import (
-	"embed"
+	"bufio"
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"os"
 	"sync"
 )
 
+// ErrNoNetwork is returned by LoadDefault when the binary was built without
+// the embed tag, so no network is embedded.
+var ErrNoNetwork = errors.New("no embedded network, build with -tags embed or set EvalFile")
+
+// networkSize is the size of the layers of a network: the input weights,
+// input bias, hidden weights and hidden bias in sequence, little endian
+const networkSize = 2*InputSize*HiddenSize + 2*HiddenSize + 2*OutputSize*HiddenDSize + 4*OutputSize
+
 // Global cache for weight loading
 var (
 	initOnce          sync.Once
 	initializationErr error
 )
 
-//go:embed default.net
-var embeddedWeights embed.FS
+var (
+	// networkHeader describes the loaded network
+	networkHeader Header
+	// networkChecksum is the hash of the loaded network
+	networkChecksum uint32
+	// networkGeneration counts the networks loaded. Evaluators compare it to
+	// the generation their cached accumulators were built with.
+	networkGeneration uint64
+)
 
 // InitializeNNUE sets up the neural network weights using the embedded file
 // This function is safe to call multiple times, weights will only be loaded once
 func InitializeNNUE() error {
 	initOnce.Do(func() {
-		initializationErr = loadDefaultWeights()
+		initializationErr = LoadDefault()
 	})
 	return initializationErr
 }
 
-// loadDefaultWeights loads weights from the embedded file when using build tag 'embed'
-func loadDefaultWeights() error {
-	const filename = "default.net"
-
-	// Open the embedded file
-	weightFile, err := embeddedWeights.Open(filename)
+// LoadFile loads the network in the file at path. See LoadReader.
+func LoadFile(path string) error {
+	f, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("could not open embedded weights file: %v", err)
+		return err
 	}
-	defer weightFile.Close()
+	defer f.Close()
 
-	// Load Weights from the file
-	if err := loadWeights(weightFile); err != nil {
-		return fmt.Errorf("error loading embedded weights: %v", err)
+	if err := LoadReader(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("load network %v failed: %w", path, err)
 	}
-
-	fmt.Println("Loaded embedded NNUE weights")
 	return nil
 }
 
-// LoadWeights loads network weights from a binary file.
-// The file is expected to contain input weights, input bias, hidden weights, and hidden bias in sequence.
-func loadWeights(r io.Reader) error {
-	// Read input weights
-	for i := 0; i < InputSize; i++ {
-		for j := 0; j < HiddenSize; j++ {
-
-			var val int16
-			if err := binary.Read(r, binary.LittleEndian, &val); err != nil {
-				return fmt.Errorf("error reading input weights: %v", err)
-			}
-			InputWeights[i][j] = val
-		}
+// LoadReader loads a network from r, replacing the current one. Networks in
+// the container format must match the dimensions in defs.go and their hash.
+// Legacy networks, without header, are only checked to be exactly the size of
+// a network of these dimensions. On error the current network is kept.
+// Networks must only be loaded while no evaluator is in use; evaluators pick
+// up the new network on their next Reset.
+func LoadReader(r io.Reader) error {
+	maxSize := binary.Size(fileHeader{}) + binary.Size(fileHeaderV2{}) + maxDescription + networkSize + 4
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return err
 	}
 
-	// Read input bias
-	for i := 0; i < HiddenSize; i++ {
-		var val int16
-		if err := binary.Read(r, binary.LittleEndian, &val); err != nil {
-			return fmt.Errorf("error reading input weights: %v", err)
+	var header Header
+	var layers []byte
+	var checksum uint32
+	if bytes.HasPrefix(data, formatMagic[:]) {
+		header, layers, checksum, err = parseContainer(data)
+		if err != nil {
+			return err
 		}
-		InputBias[i] = val
+	} else {
+		if err := checkLegacy(data); err != nil {
+			return err
+		}
+		header, layers, checksum = NewHeader(""), data, crc32.ChecksumIEEE(data)
+		header.Version = 0
 	}
 
-	// Read hidden weights
-	for i := 0; i < OutputSize; i++ {
-		for j := 0; j < HiddenDSize; j++ {
-			var val int16
-			if err := binary.Read(r, binary.LittleEndian, &val); err != nil {
-				return fmt.Errorf("error reading input weights: %v", err)
-			}
-			HiddenWeights[i][j] = val
-		}
+	loadWeights(layers)
+	networkHeader, networkChecksum = header, checksum
+	networkGeneration++
+	return nil
+}
+
+// LoadedHeader returns the header of the loaded network. Legacy networks get
+// the header of this build, with version 0 and no description.
+func LoadedHeader() Header {
+	return networkHeader
+}
+
+// Checksum returns the hash of the loaded network, which tells networks
+// apart: the trailing hash of the container format, or the CRC-32 of a legacy
+// file. It is 0 while no network is loaded.
+func Checksum() uint32 {
+	return networkChecksum
+}
+
+// loadWeights copies the weights of a network file into the network.
+func loadWeights(data []byte) {
+	next16 := func() int16 {
+		v := int16(binary.LittleEndian.Uint16(data))
+		data = data[2:]
+		return v
 	}
 
-	// Read hidden bias
-	for i := 0; i < OutputSize; i++ {
-		var val int32
-		if err := binary.Read(r, binary.LittleEndian, &val); err != nil {
-			return fmt.Errorf("error reading input weights: %v", err)
+	for i := range InputSize {
+		for j := range HiddenSize {
+			InputWeights[i][j] = next16()
 		}
-		HiddenBias[i] = val
 	}
-
-	return nil
+	for i := range HiddenSize {
+		InputBias[i] = next16()
+	}
+	for i := range OutputSize {
+		for j := range HiddenDSize {
+			HiddenWeights[i][j] = next16()
+		}
+	}
+	for i := range OutputSize {
+		HiddenBias[i] = int32(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+	}
 }
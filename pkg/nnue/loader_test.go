@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+package nnue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tecu23/argov2/pkg/board"
+)
+
+// keepNetwork restores the loaded network when the test ends
+func keepNetwork(t *testing.T) {
+	t.Helper()
+	inputWeights := new([InputSize][HiddenSize]int16)
+	*inputWeights = InputWeights
+	inputBias, hiddenWeights, hiddenBias := InputBias, HiddenWeights, HiddenBias
+	checksum := networkChecksum
+
+	t.Cleanup(func() {
+		InputWeights = *inputWeights
+		InputBias, HiddenWeights, HiddenBias = inputBias, hiddenWeights, hiddenBias
+		networkChecksum = checksum
+		networkGeneration++
+	})
+}
+
+// randomNetwork returns a network file with small random weights
+func randomNetwork(seed int64) []byte {
+	r := rand.New(rand.NewSource(seed))
+	data := make([]byte, 0, networkSize)
+	for range (networkSize - 4*OutputSize) / 2 {
+		data = binary.LittleEndian.AppendUint16(data, uint16(int16(r.Intn(64)-32)))
+	}
+	for range OutputSize {
+		data = binary.LittleEndian.AppendUint32(data, uint32(int32(r.Intn(1<<22)-1<<21)))
+	}
+	return data
+}
+
+func TestLoadReader(t *testing.T) {
+	keepNetwork(t)
+
+	data := randomNetwork(1)
+	if err := LoadReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	if got, want := Checksum(), crc32.ChecksumIEEE(data); got != want {
+		t.Errorf("Checksum = %08x, want %08x", got, want)
+	}
+
+	// Spot check every part of the file
+	at := func(offset int) int16 {
+		return int16(binary.LittleEndian.Uint16(data[offset:]))
+	}
+	inputBias := 2 * InputSize * HiddenSize
+	hiddenWeights := inputBias + 2*HiddenSize
+	hiddenBias := hiddenWeights + 2*OutputSize*HiddenDSize
+	if InputWeights[3][5] != at(2*(3*HiddenSize+5)) ||
+		InputWeights[InputSize-1][HiddenSize-1] != at(inputBias-2) ||
+		InputBias[7] != at(inputBias+14) ||
+		HiddenWeights[0][HiddenDSize-1] != at(hiddenBias-2) ||
+		HiddenBias[0] != int32(binary.LittleEndian.Uint32(data[hiddenBias:])) {
+		t.Error("the weights don't match the file")
+	}
+
+	// Files of the wrong size are rejected and keep the network
+	checksum := Checksum()
+	for _, bad := range [][]byte{data[:len(data)-1], append(data, 0), nil} {
+		if err := LoadReader(bytes.NewReader(bad)); err == nil {
+			t.Errorf("LoadReader of %d bytes succeeded, want an error", len(bad))
+		}
+	}
+	if Checksum() != checksum || InputBias[7] != at(inputBias+14) {
+		t.Error("a failed load changed the network")
+	}
+}
+
+// containerNetwork wraps the layers of a network into the container format,
+// with the header given
+func containerNetwork(h fileHeader, layers []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &h)
+	buf.Write(layers)
+	return binary.LittleEndian.AppendUint32(buf.Bytes(), crc32.ChecksumIEEE(buf.Bytes()))
+}
+
+// TestLoadContainer checks the loading of networks in version 1 of the
+// container format, whose header only tells the dimensions.
+func TestLoadContainer(t *testing.T) {
+	keepNetwork(t)
+
+	layers := randomNetwork(5)
+	header := fileHeader{formatMagic, 1, InputSize, HiddenSize, OutputSize}
+	data := containerNetwork(header, layers)
+	if err := LoadReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	if got, want := Checksum(), binary.LittleEndian.Uint32(data[len(data)-4:]); got != want {
+		t.Errorf("Checksum = %08x, want the hash of the file %08x", got, want)
+	}
+	inputBias := 2 * InputSize * HiddenSize
+	if InputBias[7] != int16(binary.LittleEndian.Uint16(layers[inputBias+14:])) {
+		t.Error("the weights don't match the layers of the file")
+	}
+
+	withHeader := func(change func(h *fileHeader)) []byte {
+		h := header
+		change(&h)
+		return containerNetwork(h, layers)
+	}
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 1
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Version", withHeader(func(h *fileHeader) { h.Version = FormatVersion + 1 })},
+		{"Version 0", withHeader(func(h *fileHeader) { h.Version = 0 })},
+		{"Input size", withHeader(func(h *fileHeader) { h.InputSize = InputSize / 2 })},
+		{"Hidden size", withHeader(func(h *fileHeader) { h.HiddenSize = 256 })},
+		{"Output size", withHeader(func(h *fileHeader) { h.OutputSize = 2 })},
+		{"Hash mismatch", corrupt},
+		{"Truncated layers", append(data[:len(data)-5:len(data)-5], data[len(data)-4:]...)},
+		{"Truncated header", data[:10]},
+	}
+
+	checksum := Checksum()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadReader(bytes.NewReader(tt.data)); err == nil {
+				t.Error("LoadReader succeeded, want an error")
+			}
+			if Checksum() != checksum {
+				t.Error("a failed load changed the network")
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	keepNetwork(t)
+
+	path := filepath.Join(t.TempDir(), "test.net")
+	if err := os.WriteFile(path, randomNetwork(2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if err := LoadFile(path + ".missing"); err == nil {
+		t.Error("LoadFile of a missing file succeeded, want an error")
+	}
+}
+
+// TestSwapNetwork checks that an evaluator evaluates with a network loaded
+// after it was created just like a new evaluator does.
+func TestSwapNetwork(t *testing.T) {
+	keepNetwork(t)
+
+	b, _ := board.ParseFEN("r1bqkbnr/ppp2ppp/2np4/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 4")
+
+	if err := LoadReader(bytes.NewReader(randomNetwork(3))); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEvaluator()
+	e.Reset(&b)
+	first := e.Evaluate(&b)
+
+	if err := LoadReader(bytes.NewReader(randomNetwork(4))); err != nil {
+		t.Fatal(err)
+	}
+	e.Reset(&b)
+	swapped := e.Evaluate(&b)
+
+	fresh := NewEvaluator()
+	fresh.Reset(&b)
+	if want := fresh.Evaluate(&b); swapped != want {
+		t.Errorf("evaluation after the swap = %d, want %d", swapped, want)
+	}
+	if swapped == first {
+		t.Errorf("evaluation didn't change with the network: %d", first)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2025 Tecu23
+// Port of Koivisto evaluation, licensed under GNU GPL v3
+
+//go:build !embed
+// +build !embed
+
+// Package nnue keeps the NNUE (Efficiently Updated Neural Network) responsible for
+// evaluation the current position
+package nnue
+
+// LoadDefault fails without build tag 'embed', as no network is embedded.
+// A network is then loaded with LoadFile.
+func LoadDefault() error {
+	return ErrNoNetwork
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+// Package pgn reads and writes chess games in Portable Game Notation.
+// Games are parsed into the sequence of board positions and moves that make
+// up the main line, together with tag pairs, comments, NAGs and variations.
+package pgn
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/board"
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// Game results as they appear in the movetext and in the Result tag.
+const (
+	WhiteWins  = "1-0"
+	BlackWins  = "0-1"
+	Draw       = "1/2-1/2"
+	Unfinished = "*"
+)
+
+// sevenTagRoster lists the mandatory PGN tags in their required export order.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// Tag is a single PGN tag pair such as [Event "Casual Game"].
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Node is a single move in the movetext together with its annotations.
+type Node struct {
+	Move       move.Move // The move itself
+	SAN        string    // The move in Standard Algebraic Notation
+	PreComment string    // Comment written before the move
+	Comment    string    // Comment written after the move
+	NAGs       []int     // Numeric annotation glyphs ($1 for "!", $2 for "?", ...)
+	Variations [][]*Node // Alternative lines that replace this move
+}
+
+// Game is a parsed PGN game. Boards[i] holds the position before Moves[i] is
+// played, so Boards always has one more entry than Moves and its last entry is
+// the final position of the main line.
+type Game struct {
+	Tags    []Tag
+	Comment string // Comment written before the first move
+	Moves   []*Node
+	Boards  []board.Board
+	Result  string
+}
+
+// NewGame creates an empty game starting from the given position. The seven
+// tag roster is filled with placeholder values and a FEN tag is added when the
+// position is not the standard starting position. Chess960 games also get a
+// Variant tag.
+func NewGame(start board.Board) *Game {
+	g := &Game{
+		Boards: []board.Board{start},
+		Result: Unfinished,
+	}
+
+	for _, name := range sevenTagRoster {
+		g.SetTag(name, "?")
+	}
+	g.SetTag("Result", Unfinished)
+
+	if start.Chess960 {
+		g.SetTag("Variant", "Chess960")
+	}
+	if fen := start.FEN(); fen != strings.TrimSpace(StartPosition) {
+		g.SetTag("SetUp", "1")
+		g.SetTag("FEN", fen)
+	}
+
+	return g
+}
+
+// Tag returns the value of the tag with the given name, or an empty string.
+func (g *Game) Tag(name string) string {
+	for _, tag := range g.Tags {
+		if tag.Name == name {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// SetTag sets the value of a tag, adding it if it does not exist yet.
+func (g *Game) SetTag(name, value string) {
+	for i := range g.Tags {
+		if g.Tags[i].Name == name {
+			g.Tags[i].Value = value
+			return
+		}
+	}
+	g.Tags = append(g.Tags, Tag{Name: name, Value: value})
+}
+
+// Position returns the final position of the main line.
+func (g *Game) Position() *board.Board {
+	return &g.Boards[len(g.Boards)-1]
+}
+
+// AddMove plays a legal move at the end of the main line. The comment is
+// attached after the move and may be empty.
+func (g *Game) AddMove(mv move.Move, comment string) error {
+	current := g.Position()
+
+	next := current.CopyBoard()
+	if !next.MakeMove(mv, board.AllMoves) {
+		return fmt.Errorf("pgn: illegal move %s", mv)
+	}
+
+	g.Moves = append(g.Moves, &Node{
+		Move:    mv,
+		SAN:     current.SAN(mv),
+		Comment: comment,
+	})
+	g.Boards = append(g.Boards, next)
+	return nil
+}
+
+// SetResult sets the game result in both the movetext and the Result tag.
+func (g *Game) SetResult(result string) {
+	g.Result = result
+	g.SetTag("Result", result)
+}
+
+// EngineComment formats search information as an engine annotation in the
+// common "score/depth time" style, e.g. "+0.35/12 1.250s" or "+M3/9 0.120s".
+// Scores are given from the point of view of the side that made the move.
+func EngineComment(si SearchInfo) string {
+	var score string
+	if si.Score.Mate != 0 {
+		if si.Score.Mate > 0 {
+			score = fmt.Sprintf("+M%d", si.Score.Mate)
+		} else {
+			score = fmt.Sprintf("-M%d", -si.Score.Mate)
+		}
+	} else {
+		score = fmt.Sprintf("%+.2f", float64(si.Score.Centipawns)/100)
+	}
+
+	return fmt.Sprintf("%s/%d %.3fs", score, si.Depth, si.Time.Seconds())
+}
@@ -0,0 +1,514 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package pgn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+// tokenKind identifies the kind of a PGN token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenSymbol
+	tokenString
+	tokenComment
+	tokenNAG
+	tokenPeriod
+	tokenOpenBracket
+	tokenCloseBracket
+	tokenOpenParen
+	tokenCloseParen
+	tokenResult
+)
+
+// token is a single lexical element of a PGN file
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// suffixNAGs maps move suffix annotations to their numeric annotation glyphs
+var suffixNAGs = map[string]int{
+	"!":  1,
+	"?":  2,
+	"!!": 3,
+	"??": 4,
+	"!?": 5,
+	"?!": 6,
+}
+
+// Reader reads PGN games one at a time from an input stream.
+type Reader struct {
+	r      *bufio.Reader
+	line   int
+	peeked *token
+}
+
+// NewReader creates a Reader that parses games from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r), line: 1}
+}
+
+// ReadAll parses every game in r.
+func ReadAll(r io.Reader) ([]*Game, error) {
+	reader := NewReader(r)
+
+	var games []*Game
+	for {
+		g, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return games, nil
+		}
+		if err != nil {
+			return games, err
+		}
+		games = append(games, g)
+	}
+}
+
+// Read parses the next game. It returns io.EOF when there are no more games.
+func (r *Reader) Read() (*Game, error) {
+	tok, err := r.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokenEOF {
+		return nil, io.EOF
+	}
+
+	g := &Game{}
+	if err := r.readTags(g); err != nil {
+		return nil, err
+	}
+
+	start, err := startPosition(g)
+	if err != nil {
+		return nil, err
+	}
+	g.Boards = []board.Board{start}
+
+	if err := r.readMovetext(g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// readTags parses the tag pair section of a game.
+func (r *Reader) readTags(g *Game) error {
+	for {
+		tok, err := r.peek()
+		if err != nil {
+			return err
+		}
+		if tok.kind != tokenOpenBracket {
+			return nil
+		}
+		r.next()
+
+		name, err := r.expect(tokenSymbol)
+		if err != nil {
+			return err
+		}
+		value, err := r.expect(tokenString)
+		if err != nil {
+			return err
+		}
+		if _, err := r.expect(tokenCloseBracket); err != nil {
+			return err
+		}
+
+		g.Tags = append(g.Tags, Tag{Name: name.value, Value: value.value})
+	}
+}
+
+// readMovetext parses the main line of a game and its result.
+func (r *Reader) readMovetext(g *Game) error {
+	moves, boards, result, err := r.readLine(g.Boards[0], 0, &g.Comment)
+	if err != nil {
+		return err
+	}
+
+	g.Moves = moves
+	g.Boards = boards
+	g.Result = result
+	if g.Result == "" {
+		g.Result = g.Tag("Result")
+	}
+	if g.Result == "" {
+		g.Result = Unfinished
+	}
+
+	return nil
+}
+
+// readLine parses a sequence of moves starting from the given position until
+// the end of the variation (depth > 0) or the end of the game (depth 0). It
+// returns the moves, the positions before each move plus the final position,
+// and the game result if one terminated the line. Comments that appear before
+// the first move are stored in leading.
+func (r *Reader) readLine(
+	start board.Board,
+	depth int,
+	leading *string,
+) ([]*Node, []board.Board, string, error) {
+	var nodes []*Node
+	boards := []board.Board{start}
+	pending := ""
+
+	for {
+		tok, err := r.peek()
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		// A new tag section starts the next game even without a result
+		if tok.kind == tokenEOF || tok.kind == tokenOpenBracket {
+			if depth > 0 {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: unterminated variation", tok.line)
+			}
+			if pending != "" {
+				appendComment(leading, pending)
+			}
+			return nodes, boards, "", nil
+		}
+		r.next()
+
+		switch tok.kind {
+		case tokenPeriod:
+			// Move number indications are not needed to follow the game
+
+		case tokenSymbol:
+			// Move number indication such as "12" in "12." or "12..."
+			if _, err := strconv.Atoi(tok.value); err == nil {
+				continue
+			}
+
+			current := boards[len(boards)-1]
+			mv, err := current.ParseSAN(tok.value)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: %v", tok.line, err)
+			}
+
+			next := current.CopyBoard()
+			next.MakeMove(mv, board.AllMoves)
+
+			nodes = append(nodes, &Node{
+				Move:       mv,
+				SAN:        current.SAN(mv),
+				PreComment: pending,
+			})
+			boards = append(boards, next)
+			pending = ""
+
+		case tokenComment:
+			switch {
+			case len(nodes) == 0 && pending == "" && leading != nil:
+				appendComment(leading, tok.value)
+			case len(nodes) == 0 || pending != "":
+				appendComment(&pending, tok.value)
+			default:
+				appendComment(&nodes[len(nodes)-1].Comment, tok.value)
+			}
+
+		case tokenNAG:
+			if len(nodes) == 0 {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: annotation before any move", tok.line)
+			}
+			last := nodes[len(nodes)-1]
+			last.NAGs = append(last.NAGs, nagValue(tok.value))
+
+		case tokenOpenParen:
+			if len(nodes) == 0 {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: variation before any move", tok.line)
+			}
+
+			// The variation replaces the last move, so it starts from the
+			// position before that move was played
+			variation, _, _, err := r.readLine(boards[len(boards)-2], depth+1, nil)
+			if err != nil {
+				return nil, nil, "", err
+			}
+			last := nodes[len(nodes)-1]
+			last.Variations = append(last.Variations, variation)
+
+		case tokenCloseParen:
+			if depth == 0 {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: unexpected )", tok.line)
+			}
+			return nodes, boards, "", nil
+
+		case tokenResult:
+			if depth > 0 {
+				return nil, nil, "", fmt.Errorf("pgn: line %d: result inside a variation", tok.line)
+			}
+			if pending != "" {
+				appendComment(leading, pending)
+			}
+			return nodes, boards, tok.value, nil
+
+		default:
+			return nil, nil, "", fmt.Errorf("pgn: line %d: unexpected token %q", tok.line, tok.value)
+		}
+	}
+}
+
+// startPosition returns the initial position of a game, honoring the FEN and
+// Variant tags.
+func startPosition(g *Game) (board.Board, error) {
+	fen := g.Tag("FEN")
+	if fen == "" {
+		fen = StartPosition
+	}
+
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return board.Board{}, fmt.Errorf("pgn: invalid FEN tag: %v", err)
+	}
+
+	switch strings.ToLower(g.Tag("Variant")) {
+	case "chess960", "chess 960", "fischerandom", "fischer random":
+		b.Chess960 = true
+	}
+	return b, nil
+}
+
+// appendComment adds a comment to dst, separating multiple comments by a space.
+func appendComment(dst *string, comment string) {
+	if *dst == "" {
+		*dst = comment
+		return
+	}
+	*dst += " " + comment
+}
+
+// nagValue converts a "$n" or suffix annotation token to its glyph number.
+func nagValue(s string) int {
+	if n, ok := suffixNAGs[s]; ok {
+		return n
+	}
+	n, _ := strconv.Atoi(strings.TrimPrefix(s, "$"))
+	return n
+}
+
+// expect reads the next token and fails if it is not of the given kind.
+func (r *Reader) expect(kind tokenKind) (token, error) {
+	tok, err := r.next()
+	if err != nil {
+		return token{}, err
+	}
+	if tok.kind != kind {
+		return token{}, fmt.Errorf("pgn: line %d: unexpected token %q", tok.line, tok.value)
+	}
+	return tok, nil
+}
+
+// peek returns the next token without consuming it.
+func (r *Reader) peek() (token, error) {
+	if r.peeked == nil {
+		tok, err := r.scan()
+		if err != nil {
+			return token{}, err
+		}
+		r.peeked = &tok
+	}
+	return *r.peeked, nil
+}
+
+// next consumes and returns the next token.
+func (r *Reader) next() (token, error) {
+	if r.peeked != nil {
+		tok := *r.peeked
+		r.peeked = nil
+		return tok, nil
+	}
+	return r.scan()
+}
+
+// scan reads the next token from the input, skipping whitespace, rest of line
+// comments and escaped lines.
+func (r *Reader) scan() (token, error) {
+	for {
+		c, err := r.readRune()
+		if err == io.EOF {
+			return token{kind: tokenEOF, line: r.line}, nil
+		}
+		if err != nil {
+			return token{}, err
+		}
+
+		line := r.line
+		switch {
+		case unicode.IsSpace(c):
+			continue
+		case c == '%':
+			// Escape mechanism: the whole line is ignored
+			if err := r.skipLine(); err != nil {
+				return token{}, err
+			}
+			continue
+		case c == ';':
+			comment, err := r.readUntil('\n')
+			if err != nil {
+				return token{}, err
+			}
+			return token{kind: tokenComment, value: strings.TrimSpace(comment), line: line}, nil
+		case c == '{':
+			comment, err := r.readUntil('}')
+			if err != nil {
+				return token{}, err
+			}
+			return token{kind: tokenComment, value: strings.Join(strings.Fields(comment), " "), line: line}, nil
+		case c == '"':
+			s, err := r.readString()
+			if err != nil {
+				return token{}, err
+			}
+			return token{kind: tokenString, value: s, line: line}, nil
+		case c == '$':
+			digits := r.readWhile(unicode.IsDigit)
+			if digits == "" {
+				return token{}, fmt.Errorf("pgn: line %d: $ without a number", line)
+			}
+			return token{kind: tokenNAG, value: "$" + digits, line: line}, nil
+		case c == '!' || c == '?':
+			suffix := string(c) + r.readWhile(func(c rune) bool { return c == '!' || c == '?' })
+			if _, ok := suffixNAGs[suffix]; !ok {
+				return token{}, fmt.Errorf("pgn: line %d: invalid annotation %s", line, suffix)
+			}
+			return token{kind: tokenNAG, value: suffix, line: line}, nil
+		case c == '.':
+			return token{kind: tokenPeriod, value: ".", line: line}, nil
+		case c == '*':
+			return token{kind: tokenResult, value: Unfinished, line: line}, nil
+		case c == '[':
+			return token{kind: tokenOpenBracket, value: "[", line: line}, nil
+		case c == ']':
+			return token{kind: tokenCloseBracket, value: "]", line: line}, nil
+		case c == '(':
+			return token{kind: tokenOpenParen, value: "(", line: line}, nil
+		case c == ')':
+			return token{kind: tokenCloseParen, value: ")", line: line}, nil
+		case isSymbolStart(c):
+			symbol := string(c) + r.readWhile(isSymbolContinuation)
+			switch symbol {
+			case WhiteWins, BlackWins, Draw:
+				return token{kind: tokenResult, value: symbol, line: line}, nil
+			}
+			return token{kind: tokenSymbol, value: symbol, line: line}, nil
+		default:
+			return token{}, fmt.Errorf("pgn: line %d: unexpected character %q", line, c)
+		}
+	}
+}
+
+// readRune reads a single rune and keeps track of the current line.
+func (r *Reader) readRune() (rune, error) {
+	c, _, err := r.r.ReadRune()
+	if err == nil && c == '\n' {
+		r.line++
+	}
+	return c, err
+}
+
+// unreadRune puts the last rune back into the input.
+func (r *Reader) unreadRune(c rune) {
+	_ = r.r.UnreadRune()
+	if c == '\n' {
+		r.line--
+	}
+}
+
+// readWhile reads runes as long as they satisfy the predicate.
+func (r *Reader) readWhile(accept func(rune) bool) string {
+	sb := &strings.Builder{}
+	for {
+		c, err := r.readRune()
+		if err != nil {
+			return sb.String()
+		}
+		if !accept(c) {
+			r.unreadRune(c)
+			return sb.String()
+		}
+		sb.WriteRune(c)
+	}
+}
+
+// readUntil reads runes up to the delimiter, which is consumed but not returned.
+// A missing newline at the end of the input terminates a rest of line comment.
+func (r *Reader) readUntil(delim rune) (string, error) {
+	sb := &strings.Builder{}
+	for {
+		c, err := r.readRune()
+		if err == io.EOF && delim == '\n' {
+			return sb.String(), nil
+		}
+		if err == io.EOF {
+			return "", fmt.Errorf("pgn: line %d: missing %c", r.line, delim)
+		}
+		if err != nil {
+			return "", err
+		}
+		if c == delim {
+			return sb.String(), nil
+		}
+		sb.WriteRune(c)
+	}
+}
+
+// skipLine discards the rest of the current line.
+func (r *Reader) skipLine() error {
+	_, err := r.readUntil('\n')
+	return err
+}
+
+// readString reads a quoted tag value, handling \" and \\ escapes.
+func (r *Reader) readString() (string, error) {
+	sb := &strings.Builder{}
+	for {
+		c, err := r.readRune()
+		if err == io.EOF || c == '\n' {
+			return "", fmt.Errorf("pgn: line %d: unterminated string", r.line)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			escaped, err := r.readRune()
+			if err != nil {
+				return "", fmt.Errorf("pgn: line %d: unterminated string", r.line)
+			}
+			sb.WriteRune(escaped)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+}
+
+// isSymbolStart reports whether c can start a PGN symbol token.
+func isSymbolStart(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
+
+// isSymbolContinuation reports whether c can continue a PGN symbol token.
+func isSymbolContinuation(c rune) bool {
+	return isSymbolStart(c) || strings.ContainsRune("_+#=:-/", c)
+}
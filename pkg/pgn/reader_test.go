@@ -0,0 +1,204 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package pgn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+const annotatedGame = `[Event "Annotated \"Test\""]
+[Site "?"]
+[Date "2025.01.01"]
+[Round "1"]
+[White "Argo"]
+[Black "Human"]
+[Result "1-0"]
+
+{Opening comment} 1. e4 e5 2. Nf3 {Developing} Nc6 $1 (2... d6 3. d4 (3. Bc4 Be7) 3... exd4) 3. Bb5!?
+; rest of line comment
+a6 4. Ba4 Nf6 5. O-O 1-0
+`
+
+func TestReadGame(t *testing.T) {
+	games, err := ReadAll(strings.NewReader(annotatedGame))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("Expected 1 game, got %d", len(games))
+	}
+	g := games[0]
+
+	if got := g.Tag("Event"); got != `Annotated "Test"` {
+		t.Errorf("Event tag = %q", got)
+	}
+	if g.Result != WhiteWins {
+		t.Errorf("Result = %s, want %s", g.Result, WhiteWins)
+	}
+	if g.Comment != "Opening comment" {
+		t.Errorf("Game comment = %q", g.Comment)
+	}
+
+	expected := []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Ba4", "Nf6", "O-O"}
+	if len(g.Moves) != len(expected) {
+		t.Fatalf("Expected %d moves, got %d", len(expected), len(g.Moves))
+	}
+	if len(g.Boards) != len(expected)+1 {
+		t.Fatalf("Expected %d boards, got %d", len(expected)+1, len(g.Boards))
+	}
+	for i, san := range expected {
+		if g.Moves[i].SAN != san {
+			t.Errorf("Move %d = %s, want %s", i, g.Moves[i].SAN, san)
+		}
+	}
+
+	if g.Moves[2].Comment != "Developing" {
+		t.Errorf("Comment after Nf3 = %q", g.Moves[2].Comment)
+	}
+	if g.Moves[4].Comment != "rest of line comment" {
+		t.Errorf("Comment after Bb5 = %q", g.Moves[4].Comment)
+	}
+	if len(g.Moves[3].NAGs) != 1 || g.Moves[3].NAGs[0] != 1 {
+		t.Errorf("NAGs after Nc6 = %v", g.Moves[3].NAGs)
+	}
+	if len(g.Moves[4].NAGs) != 1 || g.Moves[4].NAGs[0] != 5 {
+		t.Errorf("NAGs after Bb5 = %v", g.Moves[4].NAGs)
+	}
+
+	// The variation replaces 2... Nc6 and contains a nested variation
+	if len(g.Moves[3].Variations) != 1 {
+		t.Fatalf("Expected 1 variation on Nc6, got %d", len(g.Moves[3].Variations))
+	}
+	variation := g.Moves[3].Variations[0]
+	if len(variation) != 3 || variation[0].SAN != "d6" || variation[2].SAN != "exd4" {
+		t.Fatalf("Unexpected variation: %v", sans(variation))
+	}
+	nested := variation[1].Variations
+	if len(nested) != 1 || len(nested[0]) != 2 || nested[0][0].SAN != "Bc4" {
+		t.Errorf("Unexpected nested variation on d4")
+	}
+
+	finalFEN := "r1bqkb1r/1ppp1ppp/p1n2n2/4p3/B3P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 3 5"
+	if got := g.Position().FEN(); got != finalFEN {
+		t.Errorf("Final position = %s, want %s", got, finalFEN)
+	}
+}
+
+func TestReadMultipleGames(t *testing.T) {
+	input := `[Event "First"]
+[Result "1/2-1/2"]
+
+1. d4 d5 1/2-1/2
+
+[Event "Second"]
+[SetUp "1"]
+[FEN "7k/8/6K1/8/8/8/8/R7 w - - 0 40"]
+
+40. Ra8# 1-0
+
+[Event "Third"]
+
+1. c4 *
+`
+
+	games, err := ReadAll(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(games) != 3 {
+		t.Fatalf("Expected 3 games, got %d", len(games))
+	}
+
+	results := []string{Draw, WhiteWins, Unfinished}
+	for i, g := range games {
+		if g.Result != results[i] {
+			t.Errorf("Game %d result = %s, want %s", i, g.Result, results[i])
+		}
+	}
+
+	if games[1].Moves[0].SAN != "Ra8#" {
+		t.Errorf("Move from FEN tag position = %s", games[1].Moves[0].SAN)
+	}
+	if !games[1].Position().IsCheckmate() {
+		t.Error("Expected final position of second game to be checkmate")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Illegal Move", "1. e5 *"},
+		{"Unterminated Tag", `[Event "Test`},
+		{"Missing Tag Value", "[Event]"},
+		{"Unterminated Comment", "1. e4 {comment *"},
+		{"Unterminated Variation", "1. e4 (1. d4 *"},
+		{"Unexpected Close", "1. e4 ) *"},
+		{"Variation Before Move", "( 1. e4 ) *"},
+		{"Invalid FEN Tag", "[FEN \"8/8/8/8/8/8/8/8 w - - 0 1\"]\n\n*"},
+		{"Invalid Annotation", "1. e4 !!! *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadAll(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Expected error for %q", tt.input)
+			}
+		})
+	}
+}
+
+// sans returns the SAN of every node in the line.
+func sans(nodes []*Node) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		out[i] = n.SAN
+	}
+	return out
+}
+
+func TestReadChess960Game(t *testing.T) {
+	input := `[Event "Chess960"]
+[Variant "Chess960"]
+[SetUp "1"]
+[FEN "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9"]
+
+9. O-O *
+`
+
+	games, err := ReadAll(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	g := games[0]
+	if !g.Boards[0].Chess960 {
+		t.Error("Expected Chess960 start position")
+	}
+	if got := g.Moves[0].Move.String(); got != "f1g1" {
+		t.Errorf("Castling move = %s, want f1g1", got)
+	}
+	if got := g.Position().FEN(); got != "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRRKB b - - 2 9" {
+		t.Errorf("Unexpected final position %s", got)
+	}
+}
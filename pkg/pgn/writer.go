@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package pgn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tecu23/argov2/pkg/color"
+)
+
+// maxLineLength is the maximum length of a movetext line in exported games
+const maxLineLength = 80
+
+// Writer writes games in PGN export format.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter creates a Writer that writes games to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes a single game followed by an empty line. The seven tag roster
+// is written first in its standard order, followed by the remaining tags.
+func (w *Writer) Write(g *Game) error {
+	w.writeTags(g)
+	w.w.WriteByte('\n')
+
+	mw := &movetextWriter{w: w.w}
+	if g.Comment != "" {
+		mw.comment(g.Comment)
+	}
+
+	number, side := 1, color.WHITE
+	if len(g.Boards) > 0 {
+		number, side = g.Boards[0].FullMoveCounter, g.Boards[0].SideToMove
+	}
+	mw.line(g.Moves, number, side)
+
+	result := g.Result
+	if result == "" {
+		result = Unfinished
+	}
+	mw.token(result)
+	mw.w.WriteString("\n\n")
+
+	return w.w.Flush()
+}
+
+// String returns the game in PGN export format.
+func (g *Game) String() string {
+	sb := &strings.Builder{}
+	_ = NewWriter(sb).Write(g)
+	return sb.String()
+}
+
+// writeTags writes the tag pair section of a game.
+func (w *Writer) writeTags(g *Game) {
+	result := g.Result
+	if result == "" {
+		result = Unfinished
+	}
+
+	for _, name := range sevenTagRoster {
+		value := g.Tag(name)
+		switch {
+		case name == "Result":
+			value = result
+		case value == "":
+			value = "?"
+		}
+		writeTag(w.w, name, value)
+	}
+
+	for _, tag := range g.Tags {
+		if isRosterTag(tag.Name) {
+			continue
+		}
+		writeTag(w.w, tag.Name, tag.Value)
+	}
+}
+
+// writeTag writes a single tag pair, escaping quotes and backslashes.
+func writeTag(w *bufio.Writer, name, value string) {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	fmt.Fprintf(w, "[%s \"%s\"]\n", name, value)
+}
+
+// isRosterTag reports whether the tag is part of the seven tag roster.
+func isRosterTag(name string) bool {
+	for _, roster := range sevenTagRoster {
+		if roster == name {
+			return true
+		}
+	}
+	return false
+}
+
+// movetextWriter writes movetext tokens, wrapping lines at maxLineLength.
+type movetextWriter struct {
+	w           *bufio.Writer
+	column      int
+	attach      bool // The next token follows the previous one without a space
+	interrupted bool // A comment or variation interrupted the move sequence
+}
+
+// line writes a sequence of moves starting at the given move number and side.
+func (mw *movetextWriter) line(nodes []*Node, number int, side color.Color) {
+	mw.interrupted = true
+
+	for _, node := range nodes {
+		if node.PreComment != "" {
+			mw.comment(node.PreComment)
+		}
+
+		// Black moves only need a number at the start of a line or after an
+		// interruption such as a comment or a variation
+		if side == color.WHITE {
+			mw.token(fmt.Sprintf("%d.", number))
+		} else if mw.interrupted {
+			mw.token(fmt.Sprintf("%d...", number))
+		}
+		mw.token(node.SAN)
+		mw.interrupted = false
+
+		for _, nag := range node.NAGs {
+			mw.token(fmt.Sprintf("$%d", nag))
+		}
+		if node.Comment != "" {
+			mw.comment(node.Comment)
+		}
+
+		for _, variation := range node.Variations {
+			mw.token("(")
+			mw.attach = true
+			mw.line(variation, number, side)
+			mw.attach = true
+			mw.token(")")
+			mw.interrupted = true
+		}
+
+		if side == color.BLACK {
+			number++
+		}
+		side = side.Opp()
+	}
+}
+
+// comment writes a brace comment and marks the move sequence as interrupted.
+func (mw *movetextWriter) comment(text string) {
+	// A closing brace would terminate the comment early
+	text = strings.ReplaceAll(text, "}", ")")
+	for _, word := range strings.Fields("{" + text + "}") {
+		mw.token(word)
+	}
+	mw.interrupted = true
+}
+
+// token writes a single token, starting a new line when it would not fit.
+func (mw *movetextWriter) token(s string) {
+	if mw.column > 0 && mw.column+1+len(s) > maxLineLength {
+		mw.w.WriteByte('\n')
+		mw.column = 0
+	}
+	if mw.column > 0 && !mw.attach {
+		mw.w.WriteByte(' ')
+		mw.column++
+	}
+	mw.attach = false
+	mw.w.WriteString(s)
+	mw.column += len(s)
+}
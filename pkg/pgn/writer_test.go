@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package pgn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/Tecu23/argov2/internal/types"
+	"github.com/Tecu23/argov2/pkg/board"
+	. "github.com/Tecu23/argov2/pkg/constants"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	games, err := ReadAll(strings.NewReader(annotatedGame))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	expected := `[Event "Annotated \"Test\""]
+[Site "?"]
+[Date "2025.01.01"]
+[Round "1"]
+[White "Argo"]
+[Black "Human"]
+[Result "1-0"]
+
+{Opening comment} 1. e4 e5 2. Nf3 {Developing} 2... Nc6 $1 (2... d6 3. d4 (3.
+Bc4 Be7) 3... exd4) 3. Bb5 $5 {rest of line comment} 3... a6 4. Ba4 Nf6 5. O-O
+1-0
+
+`
+	out := games[0].String()
+	if out != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out, expected)
+	}
+
+	// Writing the game again after reading it back must be stable
+	again, err := ReadAll(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Reading written game failed: %v", err)
+	}
+	if again[0].String() != out {
+		t.Errorf("Round trip is not stable:\n%s", again[0].String())
+	}
+}
+
+func TestWriteEngineGame(t *testing.T) {
+	start, err := board.ParseFEN("7k/8/6K1/8/8/8/8/R7 w - - 0 40")
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	g := NewGame(start)
+	g.SetTag("White", "Argo")
+
+	mv, err := start.ParseSAN("Ra8")
+	if err != nil {
+		t.Fatalf("ParseSAN failed: %v", err)
+	}
+
+	info := SearchInfo{Score: UciScore{Mate: 1}, Depth: 3, Time: 25 * time.Millisecond}
+	if err := g.AddMove(mv, EngineComment(info)); err != nil {
+		t.Fatalf("AddMove failed: %v", err)
+	}
+	g.SetResult(WhiteWins)
+
+	expected := `[Event "?"]
+[Site "?"]
+[Date "?"]
+[Round "?"]
+[White "Argo"]
+[Black "?"]
+[Result "1-0"]
+[SetUp "1"]
+[FEN "7k/8/6K1/8/8/8/8/R7 w - - 0 40"]
+
+40. Ra8# {+M1/3 0.025s} 1-0
+
+`
+	if out := g.String(); out != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestNewGameFromStartPosition(t *testing.T) {
+	start, err := board.ParseFEN(StartPosition)
+	if err != nil {
+		t.Fatalf("Failed to parse FEN: %v", err)
+	}
+
+	g := NewGame(start)
+	if g.Tag("FEN") != "" || g.Tag("SetUp") != "" {
+		t.Error("Expected no FEN tag for the standard starting position")
+	}
+}
+
+func TestEngineComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     SearchInfo
+		expected string
+	}{
+		{"Positive", SearchInfo{Score: UciScore{Centipawns: 35}, Depth: 12, Time: 1250 * time.Millisecond}, "+0.35/12 1.250s"},
+		{"Negative", SearchInfo{Score: UciScore{Centipawns: -120}, Depth: 8, Time: time.Second}, "-1.20/8 1.000s"},
+		{"Mating", SearchInfo{Score: UciScore{Mate: 3}, Depth: 9, Time: 120 * time.Millisecond}, "+M3/9 0.120s"},
+		{"Mated", SearchInfo{Score: UciScore{Mate: -2}, Depth: 5}, "-M2/5 0.000s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EngineComment(tt.info); got != tt.expected {
+				t.Errorf("EngineComment() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
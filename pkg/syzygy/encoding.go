@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package syzygy
+
+// The tables below map the squares of the pieces to the index of a position in
+// a table. They use squares counted from a1, as the table files do, and are
+// filled once when the package is loaded.
+var (
+	// mapB1H1H7 numbers the squares below the a1-h8 diagonal from 0 to 27
+	mapB1H1H7 [64]int
+	// mapA1D1D4 numbers the squares of the a1-d1-d4 triangle from 0 to 9,
+	// the squares of the diagonal coming last
+	mapA1D1D4 [64]int
+	// mapKK numbers the 462 legal placements of two kings, the first one in
+	// the a1-d1-d4 triangle
+	mapKK [10][64]int
+	// binomial[k][n] is the number of ways to choose k out of n elements
+	binomial [7][64]uint64
+	// mapPawns numbers the squares a2-h7 so that the leading pawn, nearest to
+	// the edge and then lowest, has the highest number
+	mapPawns [64]int
+	// leadPawnIdx and leadPawnsSize give the index of the leading pawns and
+	// the number of their placements for every count and file
+	leadPawnIdx   [6][64]uint64
+	leadPawnsSize [6][4]uint64
+)
+
+func init() {
+	code := 0
+	for sq := 0; sq < 64; sq++ {
+		if offA1H8(sq) < 0 {
+			mapB1H1H7[sq] = code
+			code++
+		}
+	}
+
+	var diagonal []int
+	code = 0
+	for sq := 0; sq <= 27; sq++ { // a1 to d4
+		if offA1H8(sq) < 0 && sq%8 <= 3 {
+			mapA1D1D4[sq] = code
+			code++
+		} else if offA1H8(sq) == 0 && sq%8 <= 3 {
+			diagonal = append(diagonal, sq)
+		}
+	}
+	for _, sq := range diagonal {
+		mapA1D1D4[sq] = code
+		code++
+	}
+
+	// Placements with both kings on the diagonal are numbered last. The
+	// first king may only be on b1 for index 0, as a1 also maps there.
+	type kingPair struct{ idx, sq int }
+	var bothOnDiagonal []kingPair
+	code = 0
+	for idx := 0; idx < 10; idx++ {
+		for s1 := 0; s1 <= 27; s1++ {
+			if mapA1D1D4[s1] != idx || (idx == 0 && s1 != 1) {
+				continue
+			}
+			for s2 := 0; s2 < 64; s2++ {
+				switch {
+				case distance(s1, s2) <= 1:
+					// The kings touch
+				case offA1H8(s1) == 0 && offA1H8(s2) > 0:
+					// The first king on the diagonal, the second above it
+				case offA1H8(s1) == 0 && offA1H8(s2) == 0:
+					bothOnDiagonal = append(bothOnDiagonal, kingPair{idx, s2})
+				default:
+					mapKK[idx][s2] = code
+					code++
+				}
+			}
+		}
+	}
+	for _, p := range bothOnDiagonal {
+		mapKK[p.idx][p.sq] = code
+		code++
+	}
+
+	binomial[0][0] = 1
+	for n := 1; n < 64; n++ {
+		for k := 0; k < 7 && k <= n; k++ {
+			if k > 0 {
+				binomial[k][n] += binomial[k-1][n-1]
+			}
+			if k < n {
+				binomial[k][n] += binomial[k][n-1]
+			}
+		}
+	}
+
+	available := 47
+	for count := 1; count <= 5; count++ {
+		for file := 0; file <= 3; file++ {
+			idx := uint64(0)
+			for rank := 1; rank <= 6; rank++ {
+				sq := 8*rank + file
+				if count == 1 {
+					mapPawns[sq] = available
+					available--
+					mapPawns[sq^7] = available
+					available--
+				}
+				leadPawnIdx[count][sq] = idx
+				idx += binomial[count-1][mapPawns[sq]]
+			}
+			leadPawnsSize[count][file] = idx
+		}
+	}
+}
+
+// offA1H8 returns how far the square is above the a1-h8 diagonal
+func offA1H8(sq int) int {
+	return sq/8 - sq%8
+}
+
+func distance(s1, s2 int) int {
+	return max(abs(s1/8-s2/8), abs(s1%8-s2%8))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package syzygy
+
+import (
+	"slices"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// maxDTZ is above the rank of any move that isn't a sure win
+const maxDTZ = 1 << 18
+
+// RootMove is a legal move of the root position with its rank. Moves with a
+// higher rank keep a better result, and moves with the same rank are equally
+// good as far as the tables tell.
+type RootMove struct {
+	Move move.Move
+	Rank int
+}
+
+// RankDTZ ranks the root moves by their DTZ. Wins that can be converted
+// within the fifty-move rule all rank highest, as do losses that can't be
+// converted in time lowest, so that the search chooses among them. Wins and
+// losses the fifty-move rule turns into draws rank close to draws, the faster
+// wins and the slower losses higher. The moves are returned best first.
+func (tb *Tablebase) RankDTZ(b *board.Board) ([]RootMove, bool) {
+	if !tb.canProbe(b) {
+		return nil, false
+	}
+
+	cnt50 := int(b.HalfMoveClock)
+	rep := b.IsRepetition()
+
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	ranked := make([]RootMove, 0, moves.Len())
+	for _, mv := range moves.Moves() {
+		if !b.MakeMove(mv, board.AllMoves) {
+			continue
+		}
+
+		var dtz int
+		state := probeOK
+		switch {
+		case b.HalfMoveClock == 0:
+			// The move starts a new count, so only its result matters
+			var wdl WDL
+			wdl, state = tb.search(b, false)
+			dtz = dtzBeforeZeroing(-wdl)
+		case b.IsThreefoldRepetition() || b.IsFiftyMoveDraw():
+			dtz = 0
+		default:
+			dtz, state = tb.probeDTZ(b)
+			dtz = -dtz
+			dtz += sign(dtz)
+		}
+
+		if dtz == 2 && b.InCheck() && !hasLegalMoves(b) {
+			dtz = 1 // The move mates
+		}
+		b.UnmakeMove()
+
+		if state == probeFail {
+			return nil, false
+		}
+
+		rank := 0
+		switch {
+		case dtz > 0 && dtz+cnt50 <= 99 && !rep:
+			rank = maxDTZ
+		case dtz > 0:
+			rank = maxDTZ - (dtz + cnt50)
+		case dtz < 0 && -dtz*2+cnt50 < 100:
+			rank = -maxDTZ
+		case dtz < 0:
+			rank = -maxDTZ + (-dtz + cnt50)
+		}
+		ranked = append(ranked, RootMove{Move: mv, Rank: rank})
+	}
+
+	sortRootMoves(ranked)
+	return ranked, true
+}
+
+// RankWDL ranks the root moves by their result only, for when the DTZ tables
+// are missing. The moves are returned best first.
+func (tb *Tablebase) RankWDL(b *board.Board) ([]RootMove, bool) {
+	if !tb.canProbe(b) {
+		return nil, false
+	}
+
+	wdlRank := [5]int{-maxDTZ, -maxDTZ + 101, 0, maxDTZ - 101, maxDTZ}
+
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	ranked := make([]RootMove, 0, moves.Len())
+	for _, mv := range moves.Moves() {
+		if !b.MakeMove(mv, board.AllMoves) {
+			continue
+		}
+
+		wdl, state := Draw, probeOK
+		if !b.IsThreefoldRepetition() && !b.IsFiftyMoveDraw() {
+			wdl, state = tb.search(b, false)
+			wdl = -wdl
+		}
+		b.UnmakeMove()
+
+		if state == probeFail {
+			return nil, false
+		}
+		ranked = append(ranked, RootMove{Move: mv, Rank: wdlRank[wdl+2]})
+	}
+
+	sortRootMoves(ranked)
+	return ranked, true
+}
+
+// WDL returns the result the rank of the move stands for
+func (m RootMove) WDL() WDL {
+	switch {
+	case m.Rank == maxDTZ:
+		return Win
+	case m.Rank > 0:
+		return CursedWin
+	case m.Rank == -maxDTZ:
+		return Loss
+	case m.Rank < 0:
+		return BlessedLoss
+	}
+	return Draw
+}
+
+func sortRootMoves(moves []RootMove) {
+	slices.SortStableFunc(moves, func(a, b RootMove) int {
+		return b.Rank - a.Rank
+	})
+}
@@ -0,0 +1,437 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+// Package syzygy probes Syzygy endgame tablebases. WDL tables (.rtbw) hold the
+// result of every position of a material balance, and DTZ tables (.rtbz) the
+// distance to the next capture or pawn move that keeps the result, which is
+// enough to play a won ending out within the fifty-move rule.
+//
+// The tables are read into memory when they're first probed. Positions with
+// castling rights aren't in the tables and are never probed.
+package syzygy
+
+import (
+	"errors"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/color"
+	. "github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/move"
+)
+
+// WDL is the result of a position for the side to move. Cursed wins and
+// blessed losses are wins and losses that the fifty-move rule turns into draws.
+type WDL int
+
+const (
+	Loss        WDL = -2
+	BlessedLoss WDL = -1
+	Draw        WDL = 0
+	CursedWin   WDL = 1
+	Win         WDL = 2
+)
+
+// Tablebase is a set of tables found in the tablebase directories. It's safe
+// for concurrent use.
+type Tablebase struct {
+	tables    map[uint64]*tableEntry // By both material keys of a table
+	maxPieces int
+}
+
+// tableEntry holds the tables of a material balance. The DTZ table is nil
+// when only the WDL table was found.
+type tableEntry struct {
+	wdl, dtz *table
+}
+
+// Open finds the tables in the directories of path, separated as in the PATH
+// environment variable. The tables themselves are only read when probed.
+func Open(path string) (*Tablebase, error) {
+	tb := &Tablebase{tables: make(map[uint64]*tableEntry)}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			name, ok := strings.CutSuffix(f.Name(), ".rtbw")
+			if !ok || f.IsDir() {
+				continue
+			}
+			counts, ok := parseMaterial(name)
+			if !ok {
+				continue
+			}
+			key := counts.key()
+			if _, ok := tb.tables[key]; ok {
+				continue // Found in a directory before
+			}
+
+			entry := &tableEntry{wdl: newTable(wdlTable, filepath.Join(dir, f.Name()), counts)}
+			if dtzPath := filepath.Join(dir, name+".rtbz"); fileExists(dtzPath) {
+				entry.dtz = newTable(dtzTable, dtzPath, counts)
+			}
+			tb.tables[entry.wdl.key] = entry
+			tb.tables[entry.wdl.key2] = entry
+			tb.maxPieces = max(tb.maxPieces, entry.wdl.pieceCount)
+		}
+	}
+
+	if len(tb.tables) == 0 {
+		return nil, errors.New("no tablebase files found")
+	}
+	return tb, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// MaxPieces returns the number of pieces, kings included, of the largest
+// tables found.
+func (tb *Tablebase) MaxPieces() int {
+	return tb.maxPieces
+}
+
+// ProbeWDL returns the result of the position. It fails when the position has
+// castling rights or the tables of the position or of one of its captures are
+// missing.
+func (tb *Tablebase) ProbeWDL(b *board.Board) (WDL, bool) {
+	if !tb.canProbe(b) {
+		return Draw, false
+	}
+	wdl, state := tb.search(b, false)
+	return wdl, state != probeFail
+}
+
+// ProbeDTZ returns the number of plies to the next capture or pawn move, with
+// the sign of the result: positive when winning and negative when losing.
+// Draws are 0. Cursed wins and blessed losses are 100 plies further out.
+func (tb *Tablebase) ProbeDTZ(b *board.Board) (int, bool) {
+	if !tb.canProbe(b) {
+		return 0, false
+	}
+	dtz, state := tb.probeDTZ(b)
+	return dtz, state != probeFail
+}
+
+func (tb *Tablebase) canProbe(b *board.Board) bool {
+	return b.Castlings == 0 && b.Occupancies[color.BOTH].Count() <= tb.maxPieces
+}
+
+// search probes the position after resolving the captures, and with
+// checkZeroing the pawn moves too. The tables don't hold positions with an
+// en passant square, and in DTZ tables the value of a position whose best
+// move is a capture or pawn move is meaningless, so those results come from
+// the moves instead.
+func (tb *Tablebase) search(b *board.Board, checkZeroing bool) (WDL, probeState) {
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+
+	best := Loss
+	count := 0
+	for _, mv := range moves.Moves() {
+		if !mv.IsCapture() && (!checkZeroing || mv.GetMovingPieceType() != Pawn) {
+			continue
+		}
+		count++
+
+		if !b.MakeMove(mv, board.AllMoves) {
+			continue
+		}
+		value, state := tb.search(b, false)
+		b.UnmakeMove()
+
+		if state == probeFail {
+			return Draw, probeFail
+		}
+		if -value > best {
+			best = -value
+			if best >= Win {
+				return best, probeZeroingBestMove
+			}
+		}
+	}
+
+	// With every move searched, the table isn't needed
+	noMoreMoves := count > 0 && count == moves.Len()
+	value := best
+	if !noMoreMoves {
+		v, state := tb.probeTable(b, wdlTable, Draw)
+		if state == probeFail {
+			return Draw, probeFail
+		}
+		value = WDL(v)
+	}
+
+	if best >= value {
+		if best > Draw || noMoreMoves {
+			return best, probeZeroingBestMove
+		}
+		return best, probeOK
+	}
+	return value, probeOK
+}
+
+// probeDTZ returns the DTZ of the position. DTZ tables often hold only one
+// side to move, and the DTZ of the other side is found by a search of one ply.
+func (tb *Tablebase) probeDTZ(b *board.Board) (int, probeState) {
+	wdl, state := tb.search(b, true)
+	if state == probeFail || wdl == Draw {
+		return 0, state
+	}
+	if state == probeZeroingBestMove {
+		return dtzBeforeZeroing(wdl), state
+	}
+
+	dtz, state := tb.probeTable(b, dtzTable, wdl)
+	if state == probeFail {
+		return 0, probeFail
+	}
+	if state != probeChangeSTM {
+		if wdl == CursedWin || wdl == BlessedLoss {
+			dtz += 100
+		}
+		return dtz * sign(int(wdl)), state
+	}
+
+	minDTZ := 0xFFFF
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	for _, mv := range moves.Moves() {
+		zeroing := mv.IsCapture() || mv.GetMovingPieceType() == Pawn
+		if !b.MakeMove(mv, board.AllMoves) {
+			continue
+		}
+
+		// A capture or pawn move starts a new count, so its DTZ is the one
+		// of the move itself, with the sign of the result after it
+		var dtz int
+		if zeroing {
+			var value WDL
+			value, state = tb.search(b, false)
+			dtz = -dtzBeforeZeroing(value)
+		} else {
+			dtz, state = tb.probeDTZ(b)
+			dtz = -dtz
+		}
+
+		if dtz == 1 && b.InCheck() && !hasLegalMoves(b) {
+			minDTZ = 1 // The move mates
+		}
+		if !zeroing {
+			dtz += sign(dtz)
+		}
+		if dtz < minDTZ && sign(dtz) == sign(int(wdl)) {
+			minDTZ = dtz
+		}
+		b.UnmakeMove()
+
+		if state == probeFail {
+			return 0, probeFail
+		}
+	}
+
+	// Without legal moves the position is mate
+	if minDTZ == 0xFFFF {
+		return -1, probeOK
+	}
+	return minDTZ, probeOK
+}
+
+// dtzBeforeZeroing returns the DTZ of a position whose best move is a capture
+// or a pawn move
+func dtzBeforeZeroing(wdl WDL) int {
+	switch wdl {
+	case Win:
+		return 1
+	case CursedWin:
+		return 101
+	case BlessedLoss:
+		return -101
+	case Loss:
+		return -1
+	}
+	return 0
+}
+
+// probeTable probes the table of the material of the position
+func (tb *Tablebase) probeTable(b *board.Board, typ tableType, wdl WDL) (int, probeState) {
+	pos := newPosition(b)
+	if bits.OnesCount64(pos.occupied()) == 2 {
+		return int(Draw), probeOK // Only the kings are left
+	}
+
+	entry, ok := tb.tables[pos.materialKey()]
+	if !ok {
+		return 0, probeFail
+	}
+	t := entry.wdl
+	if typ == dtzTable {
+		t = entry.dtz
+	}
+	if t == nil {
+		return 0, probeFail
+	}
+	return t.probe(&pos, wdl)
+}
+
+func hasLegalMoves(b *board.Board) bool {
+	var moves move.List
+	b.GenerateLegalMoves(&moves)
+	return moves.Len() > 0
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// position is the part of a board the tables need, with squares counted from
+// a1 and pieces coded as in the table files: 1 to 6 for the white pawn to
+// king and 9 to 14 for the black pieces.
+type position struct {
+	pieces [16]uint64 // Bitboards by piece code
+	stm    int        // 0 for white, 1 for black
+}
+
+func newPosition(b *board.Board) position {
+	var pos position
+	for piece := WP; piece <= BK; piece++ {
+		// Mirroring the ranks turns the board squares, counted from a8, into
+		// squares counted from a1
+		pos.pieces[pieceCode(piece)] = bits.ReverseBytes64(uint64(b.Bitboards[piece]))
+	}
+	if b.SideToMove == color.BLACK {
+		pos.stm = 1
+	}
+	return pos
+}
+
+// pieceCode converts a board piece to the piece code of the tables
+func pieceCode(piece int) int {
+	if piece >= BP {
+		return piece - BP + 9
+	}
+	return piece + 1
+}
+
+func (pos *position) occupied() uint64 {
+	var occupied uint64
+	for _, bb := range pos.pieces {
+		occupied |= bb
+	}
+	return occupied
+}
+
+func (pos *position) pieceAt(sq int) int {
+	for code, bb := range pos.pieces {
+		if bb&(1<<sq) != 0 {
+			return code
+		}
+	}
+	return 0
+}
+
+func (pos *position) materialKey() uint64 {
+	var counts material
+	for code, bb := range pos.pieces {
+		counts[code] = bits.OnesCount64(bb)
+	}
+	return counts.key()
+}
+
+func lsb(bb uint64) int {
+	return bits.TrailingZeros64(bb)
+}
+
+// material is the number of pieces of each piece code
+type material [16]int
+
+// parseMaterial reads the material of a table name such as KRPvKR, which
+// lists the pieces of white and then of black.
+func parseMaterial(name string) (material, bool) {
+	var counts material
+	white, black, ok := strings.Cut(name, "v")
+	if !ok {
+		return counts, false
+	}
+
+	total := 0
+	for i, side := range []string{white, black} {
+		if !strings.HasPrefix(side, "K") || strings.Count(side, "K") != 1 {
+			return counts, false
+		}
+		for _, r := range side {
+			pieceType := strings.IndexRune("PNBRQK", r)
+			if pieceType == -1 {
+				return counts, false
+			}
+			counts[pieceType+1+8*i]++
+			total++
+		}
+	}
+	return counts, total <= maxPieces
+}
+
+// key returns a key unique to the material
+func (m material) key() uint64 {
+	var key uint64
+	for code, n := range m {
+		key |= uint64(n) << (4 * code)
+	}
+	return key
+}
+
+// swap returns the material with the colors swapped
+func (m material) swap() material {
+	var swapped material
+	for code, n := range m {
+		swapped[code^8] = n
+	}
+	return swapped
+}
+
+// newTable creates a table of the material in the table name, which is the
+// material with the stronger side as white.
+func newTable(typ tableType, path string, m material) *table {
+	t := &table{
+		typ:  typ,
+		path: path,
+		key:  m.key(),
+		key2: m.swap().key(),
+	}
+
+	for code, n := range m {
+		t.pieceCount += n
+		if n == 1 && code&7 != 6 { // Kings don't make a table unique
+			t.hasUniquePieces = true
+		}
+	}
+
+	whitePawns, blackPawns := m[1], m[9]
+	t.hasPawns = whitePawns+blackPawns > 0
+
+	// The leading color is the one with fewer pawns, if both have some
+	if blackPawns == 0 || (whitePawns > 0 && blackPawns >= whitePawns) {
+		t.pawnCount = [2]int{whitePawns, blackPawns}
+	} else {
+		t.pawnCount = [2]int{blackPawns, whitePawns}
+	}
+	return t
+}
@@ -0,0 +1,295 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package syzygy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/board"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+// tablesDir holds the KRvK, KBvK and KQvKR WDL and DTZ tables of TestProbe,
+// which are small enough to keep in the repository.
+const tablesDir = "testdata"
+
+func mustParseFEN(t *testing.T, fen string) board.Board {
+	t.Helper()
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		t.Fatalf("ParseFEN(%q) failed: %v", fen, err)
+	}
+	return b
+}
+
+func TestEncoding(t *testing.T) {
+	codes := make(map[int]bool)
+	for idx := range mapKK {
+		for _, code := range mapKK[idx] {
+			codes[code] = true
+		}
+	}
+	// Every code up to 461 is used, and 0 also stands for the illegal pairs
+	for code := range 462 {
+		if !codes[code] {
+			t.Errorf("mapKK doesn't use code %d", code)
+		}
+	}
+	if len(codes) != 462 {
+		t.Errorf("mapKK uses %d codes, want 462", len(codes))
+	}
+
+	if binomial[2][64-1] != 63*62/2 || binomial[3][10] != 120 {
+		t.Errorf("binomial[2][63] = %d, binomial[3][10] = %d", binomial[2][63], binomial[3][10])
+	}
+
+	// a2 leads every other pawn, and e7 is the last square
+	if mapPawns[8] != 47 || mapPawns[15] != 46 || mapPawns[52] != 0 {
+		t.Errorf("mapPawns of a2, h2, e7 = %d, %d, %d", mapPawns[8], mapPawns[15], mapPawns[52])
+	}
+	// A single leading pawn can be on one of 6 ranks
+	for file := range 4 {
+		if leadPawnsSize[1][file] != 6 {
+			t.Errorf("leadPawnsSize[1][%d] = %d, want 6", file, leadPawnsSize[1][file])
+		}
+	}
+}
+
+func TestParseMaterial(t *testing.T) {
+	tests := []struct {
+		name        string
+		valid       bool
+		pieceCount  int
+		hasPawns    bool
+		unique      bool
+		pawnCount   [2]int
+		symmetrical bool
+	}{
+		{"KRvK", true, 3, false, true, [2]int{0, 0}, false},
+		{"KRRvKR", true, 5, false, true, [2]int{0, 0}, false},
+		{"KNNvK", true, 4, false, false, [2]int{0, 0}, false},
+		{"KPvKP", true, 4, true, true, [2]int{1, 1}, true},
+		{"KPPvKP", true, 5, true, true, [2]int{1, 2}, false},
+		{"KRK", false, 0, false, false, [2]int{}, false},
+		{"KXvK", false, 0, false, false, [2]int{}, false},
+		{"KQQQQvKQQQ", false, 0, false, false, [2]int{}, false},
+	}
+
+	for _, tt := range tests {
+		m, ok := parseMaterial(tt.name)
+		if ok != tt.valid {
+			t.Errorf("parseMaterial(%q) ok = %v, want %v", tt.name, ok, tt.valid)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		tbl := newTable(wdlTable, tt.name, m)
+		if tbl.pieceCount != tt.pieceCount || tbl.hasPawns != tt.hasPawns ||
+			tbl.hasUniquePieces != tt.unique || tbl.pawnCount != tt.pawnCount ||
+			(tbl.key == tbl.key2) != tt.symmetrical {
+			t.Errorf("newTable(%q) = %d pieces, pawns %v, unique %v, pawn count %v, symmetrical %v",
+				tt.name, tbl.pieceCount, tbl.hasPawns, tbl.hasUniquePieces, tbl.pawnCount, tbl.key == tbl.key2)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("testdata/missing"); err == nil {
+		t.Error("Open of a missing directory succeeded, want an error")
+	}
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("Open of a directory without tables succeeded, want an error")
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"KRvK.rtbw", "KRvK.rtbz", "KQvKR.rtbw", "KvK.txt", "KRKR.rtbw"} {
+		if err := os.WriteFile(dir+"/"+name, []byte("not a table"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tb, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if tb.MaxPieces() != 4 {
+		t.Errorf("MaxPieces = %d, want 4", tb.MaxPieces())
+	}
+
+	// Only the kings need no table, and broken tables fail to probe
+	tests := []struct {
+		fen string
+		ok  bool
+	}{
+		{"8/8/8/4k3/8/8/8/4K3 w - - 0 1", true},
+		{"8/8/8/4k3/8/8/8/4K2R w - - 0 1", false},
+		{"8/8/8/4k3/8/8/8/R3K2R w KQ - 0 1", false},
+	}
+	for _, tt := range tests {
+		b := mustParseFEN(t, tt.fen)
+		if wdl, ok := tb.ProbeWDL(&b); ok != tt.ok || (ok && wdl != Draw) {
+			t.Errorf("ProbeWDL(%q) = %v, %v, want draw %v", tt.fen, wdl, ok, tt.ok)
+		}
+	}
+}
+
+// TestDecompressBlockEnd decodes every value of a block that ends with the
+// data, where decoding the last values reads past the end.
+func TestDecompressBlockEnd(t *testing.T) {
+	block := []byte{0xA5, 0x3C, 0xFF, 0x00, 0x81, 0x7E, 0x5A, 0xC3}
+
+	// Every bit is a code of length 1, for the symbol of value 2 or 4
+	d := &pairsData{
+		sizeofBlock: len(block),
+		span:        2,
+		numBlocks:   1,
+		maxSymLen:   1,
+		minSymLen:   1,
+		lowestSym:   []byte{0, 0},
+		btree:       []byte{2, 0xF0, 0xFF, 4, 0xF0, 0xFF},
+		base64:      []uint64{0},
+		symlen:      []uint8{0, 0},
+		blockLength: []byte{63, 0},
+		data:        append(make([]byte, 0, len(block)+readAhead), block...),
+	}
+	// The index points to the middle of every span of 2 values
+	for k := range 32 {
+		d.sparseIndex = append(d.sparseIndex, 0, 0, 0, 0, byte(2*k+1), 0)
+	}
+
+	for idx := range 64 {
+		expected := 2
+		if block[idx/8]&(0x80>>(idx%8)) != 0 {
+			expected = 4
+		}
+		if got := d.decompress(uint64(idx)); got != expected {
+			t.Errorf("decompress(%d) = %d, want %d", idx, got, expected)
+		}
+	}
+}
+
+// writeSingleValueTable writes a WDL table without pawns whose positions all
+// have the same result for each side to move, the kind of table the
+// generator writes for such material.
+func writeSingleValueTable(t *testing.T, path string, pieces []int, white, black WDL) {
+	t.Helper()
+	data := append([]byte{}, magics[wdlTable][:]...)
+	data = append(data, fileSplit, 0) // Flags and the order of the groups
+	for _, piece := range pieces {
+		data = append(data, byte(piece|piece<<4))
+	}
+	if len(data)%2 != 0 {
+		data = append(data, 0)
+	}
+	data = append(data, flagSingleValue, byte(white+2), flagSingleValue, byte(black+2))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSingleValueTables(t *testing.T) {
+	dir := t.TempDir()
+	writeSingleValueTable(t, dir+"/KRvK.rtbw", []int{6, 4, 14}, Win, Loss)
+	writeSingleValueTable(t, dir+"/KBvK.rtbw", []int{6, 3, 14}, Draw, Draw)
+
+	tb, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	tests := []struct {
+		fen      string
+		expected WDL
+	}{
+		{"8/8/8/4k3/8/8/8/4K2R w - - 0 1", Win},
+		{"8/8/8/4k3/8/8/8/4K2R b - - 0 1", Loss},
+		{"8/8/8/4k3/8/8/8/4K2r w - - 0 1", Loss}, // Black is the stronger side
+		{"7R/8/8/8/8/2k5/8/K7 b - - 0 1", Loss},
+		{"8/8/8/4k3/8/8/8/4KB2 b - - 0 1", Draw},
+		{"K7/8/8/8/8/8/5k2/6R1 b - - 0 1", Draw}, // Kxg1 takes the rook
+	}
+	for _, tt := range tests {
+		b := mustParseFEN(t, tt.fen)
+		if wdl, ok := tb.ProbeWDL(&b); !ok || wdl != tt.expected {
+			t.Errorf("ProbeWDL(%q) = %v, %v, want %v", tt.fen, wdl, ok, tt.expected)
+		}
+	}
+
+	// Without DTZ tables the moves are ranked by their result
+	b := mustParseFEN(t, "K7/8/8/8/8/8/5k2/6R1 b - - 0 1")
+	moves, ok := tb.RankWDL(&b)
+	if !ok || moves[0].Move.String() != "f2g1" || moves[0].Rank != 0 || moves[1].Rank >= 0 {
+		t.Errorf("RankWDL = %v, %v, want f2g1 drawing and the other moves losing", moves, ok)
+	}
+	if _, ok := tb.RankDTZ(&b); ok {
+		t.Error("RankDTZ succeeded without DTZ tables")
+	}
+}
+
+// TestProbe checks results against the KRvK, KQvKR and KBvK tables in
+// tablesDir.
+func TestProbe(t *testing.T) {
+	tb, err := Open(tablesDir)
+	if err != nil {
+		t.Fatalf("Open(%q) failed: %v", tablesDir, err)
+	}
+	for _, name := range []string{"KRvK", "KBvK", "KQvKR"} {
+		for _, ext := range []string{".rtbw", ".rtbz"} {
+			if _, err := os.Stat(filepath.Join(tablesDir, name+ext)); err != nil {
+				t.Errorf("table missing: %v", err)
+			}
+		}
+	}
+
+	wdlTests := []struct {
+		fen      string
+		expected WDL
+	}{
+		{"8/8/8/4k3/8/8/8/4K2R w - - 0 1", Win},
+		{"8/8/8/4k3/8/8/8/4K2R b - - 0 1", Loss},
+		{"8/8/8/4k3/8/8/8/4K2r w - - 0 1", Loss},
+		{"8/8/8/4k3/8/8/8/4KB2 w - - 0 1", Draw},
+		{"8/8/8/4k3/8/8/1r6/4K2Q w - - 0 1", Win},
+	}
+	for _, tt := range wdlTests {
+		b := mustParseFEN(t, tt.fen)
+		if wdl, ok := tb.ProbeWDL(&b); !ok || wdl != tt.expected {
+			t.Errorf("ProbeWDL(%q) = %v, %v, want %v", tt.fen, wdl, ok, tt.expected)
+		}
+	}
+
+	// Rh8 mates at once, so it's the only move with DTZ 1. Every move keeps
+	// the win, but after 49 moves without captures only the mate wins within
+	// the fifty-move rule.
+	b := mustParseFEN(t, "k7/8/1K6/8/8/8/8/7R w - - 98 80")
+	if dtz, ok := tb.ProbeDTZ(&b); !ok || dtz != 1 {
+		t.Errorf("ProbeDTZ = %d, %v, want 1", dtz, ok)
+	}
+	moves, ok := tb.RankDTZ(&b)
+	if !ok || len(moves) < 2 || moves[0].Move.String() != "h1h8" || moves[0].WDL() != Win ||
+		moves[1].WDL() != CursedWin {
+		t.Errorf("RankDTZ = %v, %v, want h1h8 winning and the other moves drawn by the fifty-move rule", moves, ok)
+	}
+	if fen := b.FEN(); fen != "k7/8/1K6/8/8/8/8/7R w - - 98 80" {
+		t.Errorf("probing changed the board to %q", fen)
+	}
+}
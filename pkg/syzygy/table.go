@@ -0,0 +1,695 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package syzygy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// maxPieces is the largest number of pieces in a Syzygy table
+const maxPieces = 7
+
+type tableType int
+
+const (
+	wdlTable tableType = iota
+	dtzTable
+)
+
+var magics = [2][4]byte{
+	wdlTable: {0x71, 0xE8, 0x23, 0x5D},
+	dtzTable: {0xD7, 0x66, 0x0C, 0xA5},
+}
+
+// Flags of the file header
+const (
+	fileSplit    = 1 // The table has a part for each side to move
+	fileHasPawns = 2
+)
+
+// Flags of the pairs data, the compressed part for a side and a file
+const (
+	flagSTM         = 1 // The DTZ table is for black to move
+	flagMapped      = 2 // DTZ values go through a value map
+	flagWinPlies    = 4 // DTZ of wins counts plies instead of moves
+	flagLossPlies   = 8 // DTZ of losses counts plies instead of moves
+	flagWide        = 16
+	flagSingleValue = 128
+)
+
+// pairsData is the compressed part of a table for a side to move and, in
+// tables with pawns, a file of the leading pawn. Values are compressed with
+// Huffman codes for symbols that expand, through a tree of pairs, into runs
+// of values.
+type pairsData struct {
+	flags       byte
+	sizeofBlock int
+	span        int // Number of values between entries of the sparse index
+	numBlocks   int
+	maxSymLen   int
+	minSymLen   int
+
+	lowestSym       []byte   // Lowest symbol of each code length, 16 bits each
+	btree           []byte   // Left and right symbol of every pair, 24 bits each
+	base64          []uint64 // Lowest code of each length, left aligned
+	symlen          []uint8  // Number of values of each symbol, less one
+	sparseIndex     []byte   // Block and offset of every span of values, 48 bits each
+	sparseIndexSize int
+	blockLength     []byte // Number of values of each block, less one, 16 bits each
+	blockLengthSize int
+	data            []byte // Compressed blocks
+
+	pieces   [maxPieces]int // Table piece codes in the order of the encoding
+	groupIdx [maxPieces + 1]uint64
+	groupLen [maxPieces + 1]int
+	mapIdx   [4]int // Offsets of the DTZ value maps for each WDL result
+}
+
+// table is a WDL or DTZ table file. The file is read and parsed the first
+// time the table is probed.
+type table struct {
+	typ  tableType
+	path string
+
+	key, key2       uint64 // Material keys with the stronger side white and black
+	pieceCount      int
+	hasPawns        bool
+	hasUniquePieces bool
+	pawnCount       [2]int // Pawns of the leading color and of the other one
+
+	once   sync.Once
+	err    error
+	data   []byte
+	items  [2][4]pairsData // By side to move and file
+	dtzMap int             // Offset of the DTZ value maps in data
+}
+
+func (t *table) sides() int {
+	if t.typ == wdlTable && t.key != t.key2 {
+		return 2
+	}
+	return 1
+}
+
+// get returns the pairs data for the side to move and the file
+func (t *table) get(stm, file int) *pairsData {
+	if t.typ == dtzTable {
+		stm = 0
+	}
+	if !t.hasPawns {
+		file = 0
+	}
+	return &t.items[stm&1][file]
+}
+
+// load reads and parses the table file once. It reports whether the table
+// can be probed.
+func (t *table) load() bool {
+	t.once.Do(func() {
+		data, err := readTable(t.path)
+		if err != nil {
+			t.err = err
+			return
+		}
+		if err := t.parse(data); err != nil {
+			t.err = fmt.Errorf("load tablebase %v failed: %w", t.path, err)
+			return
+		}
+		t.data = data
+	})
+	return t.err == nil
+}
+
+// readAhead is the number of bytes decoding reads past the symbol holding a
+// value, which can be past the end of the last block
+const readAhead = 8
+
+// readTable reads a table file into a buffer with room for the read-ahead
+// after the data, which is zero like the rest of the page of a memory mapped
+// file. Slices of the data keep the room in their capacity.
+func readTable(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, info.Size(), info.Size()+readAhead)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+var errCorrupt = errors.New("corrupt file")
+
+// parse reads the layout of the table from its header. Offsets are counted
+// from the start of the file, which keeps the alignments the same as in the
+// memory mapped files the format was made for.
+func (t *table) parse(data []byte) (err error) {
+	// A file too short for its layout fails with an index out of range
+	defer func() {
+		if recover() != nil {
+			err = errCorrupt
+		}
+	}()
+
+	if len(data) < 5 || [4]byte(data[:4]) != magics[t.typ] {
+		return errors.New("invalid magic")
+	}
+	flags := data[4]
+	if (flags&fileHasPawns != 0) != t.hasPawns || (flags&fileSplit != 0) != (t.key != t.key2) {
+		return errors.New("header doesn't match the material of the file name")
+	}
+	p := 5
+
+	sides := t.sides()
+	maxFile := 0
+	if t.hasPawns {
+		maxFile = 3
+	}
+	pp := t.hasPawns && t.pawnCount[1] > 0 // Pawns on both sides
+
+	for f := 0; f <= maxFile; f++ {
+		order := [2][2]int{{int(data[p] & 0xF), 0xF}, {int(data[p] >> 4), 0xF}}
+		if pp {
+			order[0][1] = int(data[p+1] & 0xF)
+			order[1][1] = int(data[p+1] >> 4)
+			p++
+		}
+		p++
+
+		for k := 0; k < t.pieceCount; k, p = k+1, p+1 {
+			for i := 0; i < sides; i++ {
+				piece := data[p] & 0xF
+				if i == 1 {
+					piece = data[p] >> 4
+				}
+				t.items[i][f].pieces[k] = int(piece)
+			}
+		}
+		for i := 0; i < sides; i++ {
+			t.setGroups(&t.items[i][f], order[i], f)
+		}
+	}
+	p += p & 1
+
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			p = t.items[i][f].setSizes(data, p)
+		}
+	}
+
+	if t.typ == dtzTable {
+		p = t.setDTZMap(data, p, maxFile)
+	}
+
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			d.sparseIndex = data[p:]
+			p += 6 * d.sparseIndexSize
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			d.blockLength = data[p:]
+			p += 2 * d.blockLengthSize
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			if d.numBlocks == 0 {
+				continue // A single value
+			}
+			p = (p + 0x3F) &^ 0x3F
+			d.data = data[p : p+d.numBlocks*d.sizeofBlock]
+			p += len(d.data)
+		}
+	}
+	return nil
+}
+
+// setGroups splits the pieces into the groups they're encoded in: the leading
+// pieces, the remaining pawns and runs of equal pieces. order gives the
+// position of the first two among the groups, and groupIdx the factor of
+// every group in the index of a position.
+func (t *table) setGroups(d *pairsData, order [2]int, file int) {
+	n := 0
+	firstLen := 2
+	if t.hasPawns {
+		firstLen = 0
+	} else if t.hasUniquePieces {
+		firstLen = 3
+	}
+
+	d.groupLen[n] = 1
+	for i := 1; i < t.pieceCount; i++ {
+		firstLen--
+		if firstLen > 0 || d.pieces[i] == d.pieces[i-1] {
+			d.groupLen[n]++
+		} else {
+			n++
+			d.groupLen[n] = 1
+		}
+	}
+	n++
+	d.groupLen[n] = 0
+
+	pp := t.hasPawns && t.pawnCount[1] > 0
+	next := 1
+	freeSquares := 64 - d.groupLen[0]
+	if pp {
+		next = 2
+		freeSquares -= d.groupLen[1]
+	}
+
+	idx := uint64(1)
+	for k := 0; next < n || k == order[0] || k == order[1]; k++ {
+		switch {
+		case k == order[0]: // Leading pawns or pieces
+			d.groupIdx[0] = idx
+			switch {
+			case t.hasPawns:
+				idx *= leadPawnsSize[d.groupLen[0]][file]
+			case t.hasUniquePieces:
+				idx *= 31332
+			default:
+				idx *= 462
+			}
+		case k == order[1]: // Remaining pawns
+			d.groupIdx[1] = idx
+			idx *= binomial[d.groupLen[1]][48-d.groupLen[0]]
+		default: // Remaining pieces
+			d.groupIdx[next] = idx
+			idx *= binomial[d.groupLen[next]][freeSquares]
+			freeSquares -= d.groupLen[next]
+			next++
+		}
+	}
+	d.groupIdx[n] = idx
+}
+
+// setSizes reads the compression parameters at p and returns the offset
+// after them.
+func (d *pairsData) setSizes(data []byte, p int) int {
+	d.flags = data[p]
+	p++
+
+	if d.flags&flagSingleValue != 0 {
+		d.numBlocks = 0
+		d.blockLengthSize = 0
+		d.span = 0
+		d.sparseIndexSize = 0
+		d.minSymLen = int(data[p]) // The value of every position
+		return p + 1
+	}
+
+	// The size of the table is the factor of the group after the last one
+	groups := 0
+	for groups < maxPieces && d.groupLen[groups] != 0 {
+		groups++
+	}
+	tbSize := d.groupIdx[groups]
+
+	d.sizeofBlock = 1 << data[p]
+	d.span = 1 << data[p+1]
+	d.sparseIndexSize = int((tbSize + uint64(d.span) - 1) / uint64(d.span))
+	padding := int(data[p+2])
+	d.numBlocks = int(binary.LittleEndian.Uint32(data[p+3:]))
+	d.blockLengthSize = d.numBlocks + padding
+	d.maxSymLen = int(data[p+7])
+	d.minSymLen = int(data[p+8])
+	p += 9
+
+	d.lowestSym = data[p:]
+	size := d.maxSymLen - d.minSymLen + 1
+	d.base64 = make([]uint64, size)
+
+	// The canonical Huffman codes of a length start after the codes of the
+	// lengths below, so the lowest code of each length follows from the
+	// number of codes of the next one
+	for i := size - 2; i >= 0; i-- {
+		d.base64[i] = (d.base64[i+1] + uint64(d.lowest(i)) - uint64(d.lowest(i+1))) / 2
+	}
+	for i := range d.base64 {
+		d.base64[i] <<= 64 - i - d.minSymLen
+	}
+	p += 2 * size
+
+	symCount := int(binary.LittleEndian.Uint16(data[p:]))
+	p += 2
+	d.btree = data[p:]
+	d.symlen = make([]uint8, symCount)
+	visited := make([]bool, symCount)
+	for sym := range symCount {
+		if !visited[sym] {
+			d.setSymlen(sym, visited)
+		}
+	}
+	return p + 3*symCount + symCount&1
+}
+
+// setSymlen computes the number of values a symbol expands into, less one.
+// A symbol whose right child is 0xFFF is a single value.
+func (d *pairsData) setSymlen(sym int, visited []bool) {
+	visited[sym] = true
+	right := d.right(sym)
+	if right == 0xFFF {
+		d.symlen[sym] = 0
+		return
+	}
+	left := d.left(sym)
+	if !visited[left] {
+		d.setSymlen(left, visited)
+	}
+	if !visited[right] {
+		d.setSymlen(right, visited)
+	}
+	d.symlen[sym] = d.symlen[left] + d.symlen[right] + 1
+}
+
+func (d *pairsData) lowest(i int) uint16 {
+	return binary.LittleEndian.Uint16(d.lowestSym[2*i:])
+}
+
+func (d *pairsData) left(sym int) int {
+	lr := d.btree[3*sym:]
+	return int(lr[1]&0xF)<<8 | int(lr[0])
+}
+
+func (d *pairsData) right(sym int) int {
+	lr := d.btree[3*sym:]
+	return int(lr[2])<<4 | int(lr[1]>>4)
+}
+
+// setDTZMap reads the value maps of the DTZ table at p and returns the offset
+// after them. The maps of each WDL result follow each other, every one
+// starting with its length.
+func (t *table) setDTZMap(data []byte, p, maxFile int) int {
+	t.dtzMap = p
+	for f := 0; f <= maxFile; f++ {
+		d := t.get(0, f)
+		if d.flags&flagMapped == 0 {
+			continue
+		}
+		if d.flags&flagWide != 0 {
+			p += p & 1
+			for i := range 4 {
+				d.mapIdx[i] = (p-t.dtzMap)/2 + 1
+				p += 2 + 2*int(binary.LittleEndian.Uint16(data[p:]))
+			}
+		} else {
+			for i := range 4 {
+				d.mapIdx[i] = p - t.dtzMap + 1
+				p += 1 + int(data[p])
+			}
+		}
+	}
+	return p + p&1
+}
+
+// decompress returns the value stored at idx. The sparse index points close
+// to the block and offset of the value, and the block is decoded from its
+// start up to the symbol holding the value.
+func (d *pairsData) decompress(idx uint64) int {
+	if d.flags&flagSingleValue != 0 {
+		return d.minSymLen
+	}
+
+	k := idx / uint64(d.span)
+	entry := d.sparseIndex[6*k:]
+	block := int(binary.LittleEndian.Uint32(entry))
+	offset := int(binary.LittleEndian.Uint16(entry[4:]))
+
+	// The index entry holds the value in the middle of the span
+	offset += int(idx%uint64(d.span)) - d.span/2
+
+	for offset < 0 {
+		block--
+		offset += d.blockLen(block) + 1
+	}
+	for offset > d.blockLen(block) {
+		offset -= d.blockLen(block) + 1
+		block++
+	}
+
+	// The data is sliced up to the read-ahead, which may be past its length
+	ptr := block * d.sizeofBlock
+	buf64 := binary.BigEndian.Uint64(d.data[ptr : ptr+8])
+	ptr += 8
+	buf64Size := 64
+
+	var sym int
+	for {
+		l := 0
+		for buf64 < d.base64[l] {
+			l++
+		}
+		sym = int((buf64-d.base64[l])>>(64-l-d.minSymLen)) + int(d.lowest(l))
+
+		if offset < int(d.symlen[sym])+1 {
+			break
+		}
+		offset -= int(d.symlen[sym]) + 1
+
+		l += d.minSymLen
+		buf64 <<= l
+		buf64Size -= l
+		if buf64Size <= 32 {
+			buf64Size += 32
+			buf64 |= uint64(binary.BigEndian.Uint32(d.data[ptr:ptr+4])) << (64 - buf64Size)
+			ptr += 4
+		}
+	}
+
+	// Walk down the pairs to the value
+	for d.symlen[sym] != 0 {
+		left := d.left(sym)
+		if offset < int(d.symlen[left])+1 {
+			sym = left
+		} else {
+			offset -= int(d.symlen[left]) + 1
+			sym = d.right(sym)
+		}
+	}
+	return d.left(sym)
+}
+
+func (d *pairsData) blockLen(block int) int {
+	return int(binary.LittleEndian.Uint16(d.blockLength[2*block:]))
+}
+
+// mapScore converts a DTZ table value into plies. The WDL result selects the
+// value map and tells whether the table counts moves or plies.
+func (t *table) mapScore(file, value int, wdl WDL) int {
+	d := t.get(0, file)
+	if d.flags&flagMapped != 0 {
+		m := d.mapIdx[[5]int{1, 3, 0, 2, 0}[wdl+2]] + value
+		if d.flags&flagWide != 0 {
+			value = int(binary.LittleEndian.Uint16(t.data[t.dtzMap+2*m:]))
+		} else {
+			value = int(t.data[t.dtzMap+m])
+		}
+	}
+
+	if (wdl == Win && d.flags&flagWinPlies == 0) ||
+		(wdl == Loss && d.flags&flagLossPlies == 0) ||
+		wdl == CursedWin || wdl == BlessedLoss {
+		value *= 2
+	}
+	return value + 1
+}
+
+// probeState is the outcome of a table probe beside its value
+type probeState int
+
+const (
+	probeFail            probeState = iota // The table is missing or can't be read
+	probeOK                                // The value is valid
+	probeChangeSTM                         // The DTZ table only holds the other side to move
+	probeZeroingBestMove                   // The best move is a capture or a pawn move
+)
+
+// probe returns the value of the position in the table, which must match the
+// material of the position. Tables store the positions with the stronger
+// side as white, so the colors are swapped for the other side, and with the
+// squares mirrored into a part of the board.
+func (t *table) probe(pos *position, wdl WDL) (int, probeState) {
+	if !t.load() {
+		return 0, probeFail
+	}
+
+	// A symmetric table only holds white to move
+	flip := (t.key == t.key2 && pos.stm == 1) || pos.materialKey() != t.key
+	flipColor, flipSquares, stm := 0, 0, pos.stm
+	if flip {
+		flipColor, flipSquares, stm = 8, 56, stm^1
+	}
+
+	var squares, pieces [maxPieces]int
+	size, leadPawnsCount, file := 0, 0, 0
+	var leadPawns uint64
+
+	// The leading pawns go first and pick the file of the table
+	if t.hasPawns {
+		piece := t.items[0][0].pieces[0] ^ flipColor
+		leadPawns = pos.pieces[piece]
+		for bb := leadPawns; bb != 0; bb &= bb - 1 {
+			squares[size] = lsb(bb) ^ flipSquares
+			size++
+		}
+		leadPawnsCount = size
+
+		best := 0
+		for i := 1; i < leadPawnsCount; i++ {
+			if mapPawns[squares[i]] > mapPawns[squares[best]] {
+				best = i
+			}
+		}
+		squares[0], squares[best] = squares[best], squares[0]
+		file = min(squares[0]%8, 7-squares[0]%8)
+	}
+
+	if t.typ == dtzTable && int(t.get(0, file).flags&flagSTM) != stm && (t.key != t.key2 || t.hasPawns) {
+		return 0, probeChangeSTM
+	}
+
+	for bb := pos.occupied() &^ leadPawns; bb != 0; bb &= bb - 1 {
+		sq := lsb(bb)
+		squares[size] = sq ^ flipSquares
+		pieces[size] = pos.pieceAt(sq) ^ flipColor
+		size++
+	}
+
+	d := t.get(stm, file)
+
+	// Put the pieces in the order of the table
+	for i := leadPawnsCount; i < size-1; i++ {
+		for j := i + 1; j < size; j++ {
+			if d.pieces[i] == pieces[j] {
+				pieces[i], pieces[j] = pieces[j], pieces[i]
+				squares[i], squares[j] = squares[j], squares[i]
+				break
+			}
+		}
+	}
+
+	// Mirror the leading piece onto the files a to d
+	if squares[0]%8 > 3 {
+		for i := range size {
+			squares[i] ^= 7
+		}
+	}
+
+	var idx uint64
+	if t.hasPawns {
+		idx = leadPawnIdx[leadPawnsCount][squares[0]]
+		slices.SortStableFunc(squares[1:leadPawnsCount], func(a, b int) int {
+			return mapPawns[a] - mapPawns[b]
+		})
+		for i := 1; i < leadPawnsCount; i++ {
+			idx += binomial[i][mapPawns[squares[i]]]
+		}
+	} else {
+		// Mirror the leading piece onto the ranks 1 to 4, and then below the
+		// a1-h8 diagonal when the first leading piece off it is above
+		if squares[0]/8 > 3 {
+			for i := range size {
+				squares[i] ^= 56
+			}
+		}
+		for i := 0; i < d.groupLen[0]; i++ {
+			if offA1H8(squares[i]) == 0 {
+				continue
+			}
+			if offA1H8(squares[i]) > 0 {
+				for j := i; j < size; j++ {
+					squares[j] = (squares[j]>>3 | squares[j]<<3) & 63
+				}
+			}
+			break
+		}
+
+		if t.hasUniquePieces {
+			idx = uniquePiecesIndex(squares[0], squares[1], squares[2])
+		} else {
+			idx = uint64(mapKK[mapA1D1D4[squares[0]]][squares[1]])
+		}
+	}
+
+	// The other groups are encoded by their squares in ascending order, left
+	// out the squares of the groups before
+	idx *= d.groupIdx[0]
+	groupStart := d.groupLen[0]
+	remainingPawns := t.hasPawns && t.pawnCount[1] > 0
+	for next := 1; d.groupLen[next] != 0; next++ {
+		group := squares[groupStart : groupStart+d.groupLen[next]]
+		slices.Sort(group)
+
+		n := uint64(0)
+		for i, sq := range group {
+			adjust := 0
+			for _, s := range squares[:groupStart] {
+				if sq > s {
+					adjust++
+				}
+			}
+			if remainingPawns {
+				adjust += 8
+			}
+			n += binomial[i+1][sq-adjust]
+		}
+		remainingPawns = false
+		idx += n * d.groupIdx[next]
+		groupStart += len(group)
+	}
+
+	value := d.decompress(idx)
+	if t.typ == wdlTable {
+		return value - 2, probeOK
+	}
+	return t.mapScore(file, value, wdl), probeOK
+}
+
+// uniquePiecesIndex encodes the three leading pieces of a table without pawns
+// that has a unique piece. The first piece is in the a1-d1-d4 triangle and
+// the pieces on the diagonal are encoded after the others.
+func uniquePiecesIndex(s0, s1, s2 int) uint64 {
+	adjust1, adjust2 := 0, 0
+	if s1 > s0 {
+		adjust1++
+	}
+	if s2 > s0 {
+		adjust2++
+	}
+	if s2 > s1 {
+		adjust2++
+	}
+
+	var idx int
+	switch {
+	case offA1H8(s0) != 0:
+		idx = (mapA1D1D4[s0]*63+(s1-adjust1))*62 + s2 - adjust2
+	case offA1H8(s1) != 0:
+		idx = (6*63+(s0/8)*28+mapB1H1H7[s1])*62 + s2 - adjust2
+	case offA1H8(s2) != 0:
+		idx = 6*63*62 + 4*28*62 + (s0/8)*7*28 + (s1/8-adjust1)*28 + mapB1H1H7[s2]
+	default:
+		idx = 6*63*62 + 4*28*62 + 4*7*28 + (s0/8)*7*6 + (s1/8-adjust1)*6 + (s2/8 - adjust2)
+	}
+	return uint64(idx)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package uci
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	. "github.com/Tecu23/argov2/internal/types"
+)
+
+// benchEngine records the searches it is asked to run
+type benchEngine struct {
+	clears   int
+	searches []SearchParams
+}
+
+func (e *benchEngine) Prepare() {}
+
+func (e *benchEngine) Clear() { e.clears++ }
+
+func (e *benchEngine) Search(_ context.Context, params SearchParams) SearchInfo {
+	e.searches = append(e.searches, params)
+	return SearchInfo{Nodes: int64(len(e.searches))}
+}
+
+func TestBench(t *testing.T) {
+	eng := &benchEngine{}
+	nodes, _ := Bench(eng, 7, io.Discard)
+
+	n := len(benchPositions)
+	if len(eng.searches) != n {
+		t.Fatalf("searched %d positions, want %d", len(eng.searches), n)
+	}
+	if eng.clears != n {
+		t.Errorf("engine cleared %d times, want once per position", eng.clears)
+	}
+	for i, params := range eng.searches {
+		if params.Limits != (LimitsType{Depth: 7}) {
+			t.Errorf("position %d searched with limits %+v, want depth 7 only", i+1, params.Limits)
+		}
+		if len(params.Boards) != 1 {
+			t.Errorf("position %d searched with %d boards, want 1", i+1, len(params.Boards))
+		}
+	}
+
+	if want := int64(n * (n + 1) / 2); nodes != want {
+		t.Errorf("nodes = %d, want %d", nodes, want)
+	}
+}
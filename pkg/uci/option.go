@@ -6,6 +6,7 @@ package uci
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type Option interface {
@@ -36,3 +37,108 @@ func (opt *BoolOption) Set(s string) error {
 	*opt.Value = v
 	return nil
 }
+
+type SpinOption struct {
+	Name  string
+	Min   int
+	Max   int
+	Value *int
+}
+
+func (opt *SpinOption) UciName() string {
+	return opt.Name
+}
+
+func (opt *SpinOption) UciString() string {
+	return fmt.Sprintf("option name %v type %v default %v min %v max %v",
+		opt.Name, "spin", *opt.Value, opt.Min, opt.Max)
+}
+
+func (opt *SpinOption) Set(s string) error {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	if v < opt.Min || v > opt.Max {
+		return fmt.Errorf("%v must be between %v and %v", opt.Name, opt.Min, opt.Max)
+	}
+	*opt.Value = v
+	return nil
+}
+
+type ComboOption struct {
+	Name    string
+	Options []string
+	Value   *string
+}
+
+func (opt *ComboOption) UciName() string {
+	return opt.Name
+}
+
+func (opt *ComboOption) UciString() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "option name %v type %v default %v", opt.Name, "combo", *opt.Value)
+	for _, o := range opt.Options {
+		fmt.Fprintf(sb, " var %v", o)
+	}
+	return sb.String()
+}
+
+func (opt *ComboOption) Set(s string) error {
+	for _, o := range opt.Options {
+		if strings.EqualFold(o, s) {
+			*opt.Value = o
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not a valid value for %v", s, opt.Name)
+}
+
+// emptyString is how UCI writes an empty string option value
+const emptyString = "<empty>"
+
+type StringOption struct {
+	Name  string
+	Value *string
+}
+
+func (opt *StringOption) UciName() string {
+	return opt.Name
+}
+
+func (opt *StringOption) UciString() string {
+	value := *opt.Value
+	if value == "" {
+		value = emptyString
+	}
+	return fmt.Sprintf("option name %v type %v default %v",
+		opt.Name, "string", value)
+}
+
+func (opt *StringOption) Set(s string) error {
+	if s == emptyString {
+		s = ""
+	}
+	*opt.Value = s
+	return nil
+}
+
+// ButtonOption triggers an action when set. It has no value.
+type ButtonOption struct {
+	Name   string
+	Action func()
+}
+
+func (opt *ButtonOption) UciName() string {
+	return opt.Name
+}
+
+func (opt *ButtonOption) UciString() string {
+	return fmt.Sprintf("option name %v type %v", opt.Name, "button")
+}
+
+func (opt *ButtonOption) Set(_ string) error {
+	opt.Action()
+	return nil
+}
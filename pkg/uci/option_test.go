@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package uci
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetOptionCommand(t *testing.T) {
+	var (
+		hash     = 16
+		evalFile = "default.net"
+		style    = "Normal"
+		cleared  = false
+	)
+
+	uci := &Protocol{options: []Option{
+		&SpinOption{Name: "Hash", Min: 1, Max: 1024, Value: &hash},
+		&StringOption{Name: "EvalFile", Value: &evalFile},
+		&ComboOption{Name: "Style", Options: []string{"Solid", "Normal", "Risky"}, Value: &style},
+		&ButtonOption{Name: "Clear Hash", Action: func() { cleared = true }},
+	}}
+
+	tests := []struct {
+		command string
+		wantErr bool
+	}{
+		{"name Hash value 256", false},
+		{"name hash value 4096", true},
+		{"name Hash value many", true},
+		{"name EvalFile value /home/user/my nets/big.net", false},
+		{"name Style value risky", false},
+		{"name Style value Wild", true},
+		{"name Clear Hash", false},
+		{"name Unknown Option value 1", true},
+		{"Hash value 1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			err := uci.setOptionCommand(strings.Fields(tt.command))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setoption %s: error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			}
+		})
+	}
+
+	if hash != 256 {
+		t.Errorf("Hash = %d, want 256", hash)
+	}
+	if evalFile != "/home/user/my nets/big.net" {
+		t.Errorf("EvalFile = %q, want multi-word path", evalFile)
+	}
+	if style != "Risky" {
+		t.Errorf("Style = %s, want Risky", style)
+	}
+	if !cleared {
+		t.Error("Clear Hash button was not pressed")
+	}
+}
+
+func TestOptionUciString(t *testing.T) {
+	var (
+		threads  = 1
+		evalFile = ""
+		style    = "Normal"
+	)
+
+	tests := []struct {
+		option   Option
+		expected string
+	}{
+		{&SpinOption{Name: "Threads", Min: 1, Max: 256, Value: &threads}, "option name Threads type spin default 1 min 1 max 256"},
+		{&StringOption{Name: "EvalFile", Value: &evalFile}, "option name EvalFile type string default <empty>"},
+		{&ComboOption{Name: "Style", Options: []string{"Solid", "Normal"}, Value: &style}, "option name Style type combo default Normal var Solid var Normal"},
+		{&ButtonOption{Name: "Clear Hash"}, "option name Clear Hash type button"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.option.UciString(); got != tt.expected {
+			t.Errorf("UciString() = %s, want %s", got, tt.expected)
+		}
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -34,10 +37,19 @@ type Protocol struct {
 	version      string
 	options      []Option           // a list of engine options that can be set via the UCI commands
 	engine       Engine             // The underlying chess engine instance
+	in           io.Reader          // Where the commands are read from, stdin by default
+	out          io.Writer          // Where the responses are written to, stdout by default
 	boards       []board.Board      // The stack of boards representing the current game state
 	thinking     bool               // Indicates if the engine is currently searching
 	engineOutput chan SearchInfo    // Channel used to receive SearchInfo updates from the engine
 	cancel       context.CancelFunc // Used to cancel ongoing searches
+	chess960     bool               // UCI_Chess960: castling moves use king-takes-rook notation
+	ponder       bool               // Ponder: the GUI may send "go ponder"
+	pondering    bool               // Indicates if the current search is a ponder search without ponderhit yet
+	ponderhit    chan struct{}      // Closed on ponderhit to start the clock of a ponder search
+	infinite     bool               // Indicates if the current search runs until stop
+	searchDone   bool               // Indicates if the engine finished a search whose bestmove is held back
+	searchResult SearchInfo         // The latest search info of the current search
 }
 
 // New creates a new Protocol instance with given engine name, author, version, and options.
@@ -47,14 +59,21 @@ func New(name, author, version string, engine Engine, options []Option) *Protoco
 	if err != nil {
 		panic(err)
 	}
-	return &Protocol{
+	uci := &Protocol{
 		name:    name,
 		author:  author,
 		version: version,
 		engine:  engine,
-		options: options,
+		in:      os.Stdin,
+		out:     os.Stdout,
 		boards:  []board.Board{initBoard},
 	}
+	// Cloned so the caller's slice is never written to
+	uci.options = append(slices.Clone(options),
+		&BoolOption{Name: "Ponder", Value: &uci.ponder},
+		&BoolOption{Name: "UCI_Chess960", Value: &uci.chess960},
+	)
+	return uci
 }
 
 // Run starts the main UCI loop, listening for incoming commands from stdin
@@ -65,29 +84,26 @@ func (uci *Protocol) Run(logger *log.Logger) {
 	// This goroutine coninuously reads lines from stdin and sends them to the commands channel
 	go func() {
 		defer close(commands)
-		readCommands(commands)
+		readCommands(uci.in, commands)
 	}()
 
-	var searchResult SearchInfo
 	for {
 		select {
 		// If the engine sends a SearchInfo on engineOutput:
 		case si, ok := <-uci.engineOutput:
 			if ok {
 				// Print the intermediate search info in UCI format
-				fmt.Println(searchInfoToUci(si))
-				searchResult = si
+				fmt.Fprintln(uci.out, searchInfoToUci(si))
+				uci.searchResult = si
 			} else {
-				// Engine finished searching (channel closed)
-				if len(searchResult.MainLine) != 0 {
-					// Print the best move found
-					fmt.Printf("bestmove %v\n", searchResult.MainLine[0])
-				}
-				// Reset state
-				uci.thinking = false
-				uci.cancel = nil
+				// Engine finished searching (channel closed). While pondering the
+				// bestmove is held back until the GUI sends ponderhit or stop,
+				// and in an infinite search until stop.
 				uci.engineOutput = nil
-				searchResult = SearchInfo{}
+				uci.searchDone = true
+				if !uci.pondering && !uci.infinite {
+					uci.finishSearch()
+				}
 			}
 		// When a new command arrives from stdin:
 		case commandLine, ok := <-commands:
@@ -105,10 +121,10 @@ func (uci *Protocol) Run(logger *log.Logger) {
 	}
 }
 
-// readCommands reads input lines from stdin and sends them to the provided channel.
+// readCommands reads input lines from r and sends them to the provided channel.
 // It stops reading if the "quit" command is encountered
-func readCommands(commands chan<- string) {
-	scanner := bufio.NewScanner(os.Stdin)
+func readCommands(r io.Reader, commands chan<- string) {
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		commandLine := scanner.Text()
 		if commandLine == "quit" {
@@ -134,12 +150,30 @@ func (uci *Protocol) handle(commandLine string) error {
 
 	// If engine is currently searching (thinking), only certain commands like "stop" are allowed
 	if uci.thinking {
-		if commandName == "stop" {
-			uci.cancel()
+		switch commandName {
+		case "stop":
 			// Stop the ongoing search
+			uci.cancel()
+			uci.pondering = false
+			uci.infinite = false
+		case "ponderhit":
+			if !uci.pondering {
+				return errors.New("not pondering")
+			}
+			// The opponent played the expected move, let the search continue on the clock
+			close(uci.ponderhit)
+			uci.pondering = false
+		case "isready":
+			fmt.Fprintln(uci.out, "readyok")
 			return nil
+		default:
+			return errors.New("search still run")
+		}
+
+		if uci.searchDone && !uci.infinite {
+			uci.finishSearch()
 		}
-		return errors.New("search still run")
+		return nil
 	}
 
 	/// Map commandName to the appropriate handler function
@@ -158,8 +192,8 @@ func (uci *Protocol) handle(commandLine string) error {
 		h = uci.goCommand
 	case "ucinewgame":
 		h = uci.uciNewGameCommand
-	case "ponderhit":
-		h = uci.ponderhitCommand
+	case "bench":
+		h = uci.benchCommand
 	}
 
 	if h == nil {
@@ -171,24 +205,30 @@ func (uci *Protocol) handle(commandLine string) error {
 
 // uciCommand handles the "uci" command, which requests engine identification and available options.
 func (uci *Protocol) uciCommand(_ []string) error {
-	fmt.Printf("id name %s %s\n", uci.name, uci.version)
-	fmt.Printf("id author %s\n", uci.author)
+	fmt.Fprintf(uci.out, "id name %s %s\n", uci.name, uci.version)
+	fmt.Fprintf(uci.out, "id author %s\n", uci.author)
 	// Print all available options in UCI format
 	for _, option := range uci.options {
-		fmt.Println(option.UciString())
+		fmt.Fprintln(uci.out, option.UciString())
 	}
-	fmt.Println("uciok")
+	fmt.Fprintln(uci.out, "uciok")
 	return nil
 }
 
-// setOptionCommand handle the "setoption", allowing the GUI to change engine output
+// setOptionCommand handle the "setoption", allowing the GUI to change engine output.
+// Both the option name and its value may contain spaces.
 func (uci *Protocol) setOptionCommand(fields []string) error {
-	if len(fields) < 4 {
-		// Expected output: setoption name <name> value <value>
+	// Expected output: setoption name <name> [value <value>]
+	if len(fields) < 2 || fields[0] != "name" {
 		return errors.New("invalid setoption arguments")
 	}
 
-	name, value := fields[1], fields[3]
+	name, value := strings.Join(fields[1:], " "), ""
+	if valueIndex := findIndexString(fields, "value"); valueIndex != -1 {
+		name = strings.Join(fields[1:valueIndex], " ")
+		value = strings.Join(fields[valueIndex+1:], " ")
+	}
+
 	// Try to find and set the matching option
 	for _, option := range uci.options {
 		if strings.EqualFold(option.UciName(), name) {
@@ -203,7 +243,7 @@ func (uci *Protocol) setOptionCommand(fields []string) error {
 // The engine should do any necessary initialization and then print "readyok".
 func (uci *Protocol) isReadyCommand(_ []string) error {
 	uci.engine.Prepare()
-	fmt.Println("readyok")
+	fmt.Fprintln(uci.out, "readyok")
 	return nil
 }
 
@@ -235,6 +275,12 @@ func (uci *Protocol) positionCommand(fields []string) error {
 		return err
 	}
 
+	// In Chess960 mode the GUI sends castling moves as king takes rook, even
+	// from the standard starting position
+	if uci.chess960 {
+		b.Chess960 = true
+	}
+
 	boards := []board.Board{b}
 	// If there are moves following "moves", apply them sequentially to reach the final position
 	if movesIndex >= 0 && movesIndex+1 < len(args) {
@@ -255,17 +301,28 @@ func (uci *Protocol) positionCommand(fields []string) error {
 // It creates a cancellable context and runs the search in a separate goroutine.
 // Intermediate and final results are sent to engineOutput channel.
 func (uci *Protocol) goCommand(fields []string) error {
+	if len(fields) > 0 && fields[0] == "perft" {
+		return uci.perftCommand(fields[1:])
+	}
+
 	limits := parseLimits(fields)
 	ctx, cancel := context.WithCancel(context.TODO())
 	uci.cancel = cancel
 	uci.thinking = true
+	uci.searchDone = false
 	uci.engineOutput = make(chan SearchInfo, 3)
 
+	// A ponder search runs on the opponent's time until ponderhit or stop
+	uci.pondering = limits.Ponder
+	uci.ponderhit = make(chan struct{})
+	uci.infinite = limits.Infinite
+
 	// Run the search async
 	go func() {
 		searchResult := uci.engine.Search(ctx, SearchParams{
-			Boards: uci.boards,
-			Limits: limits,
+			Boards:    uci.boards,
+			Limits:    limits,
+			PonderHit: uci.ponderhit,
 			Progress: func(si SearchInfo) {
 				// Send intermediate search info, but don't block if channel is full
 				select {
@@ -281,16 +338,68 @@ func (uci *Protocol) goCommand(fields []string) error {
 	return nil
 }
 
+// perftCommand handles the "go perft <depth>" extension. It prints the number
+// of leaf nodes below every root move of the current position and the total,
+// which helps to debug the move generator from any UCI client.
+func (uci *Protocol) perftCommand(fields []string) error {
+	if len(fields) == 0 {
+		return errors.New("missing perft depth")
+	}
+	depth, err := strconv.Atoi(fields[0])
+	if err != nil || depth < 1 {
+		return errors.New("invalid perft depth")
+	}
+
+	b := uci.boards[len(uci.boards)-1]
+	nodes := int64(0)
+	for _, result := range board.Divide(&b, depth, runtime.GOMAXPROCS(0), nil) {
+		fmt.Fprintf(uci.out, "%v: %d\n", result.Move, result.Nodes)
+		nodes += result.Nodes
+	}
+	fmt.Fprintf(uci.out, "\nNodes searched: %d\n", nodes)
+	return nil
+}
+
+// benchCommand handles the "bench [depth]" extension, which searches the bench
+// positions to a fixed depth and prints the node count and speed. The search
+// state is lost, like after "ucinewgame".
+func (uci *Protocol) benchCommand(fields []string) error {
+	depth := DefaultBenchDepth
+	if len(fields) > 0 {
+		var err error
+		if depth, err = strconv.Atoi(fields[0]); err != nil || depth < 1 {
+			return errors.New("invalid bench depth")
+		}
+	}
+
+	Bench(uci.engine, depth, uci.out)
+	return nil
+}
+
 // uciNewGameCommand signals that a new game is starting, so the engine should reset it internal state.
 func (uci *Protocol) uciNewGameCommand(_ []string) error {
 	uci.engine.Clear()
 	return nil
 }
 
-// ponderhitCommand is a UCI Command that indicates the opponent has made a move and
-// the engine should start pondering and start searching. Not yet implemented
-func (uci *Protocol) ponderhitCommand(_ []string) error {
-	return errors.New("not implemented")
+// finishSearch prints the best move of the finished search, followed by the
+// expected reply from the principal variation to ponder on, and resets the
+// search state.
+func (uci *Protocol) finishSearch() {
+	if pv := uci.searchResult.MainLine; len(pv) > 1 {
+		fmt.Fprintf(uci.out, "bestmove %v ponder %v\n", pv[0], pv[1])
+	} else if len(pv) != 0 {
+		fmt.Fprintf(uci.out, "bestmove %v\n", pv[0])
+	}
+
+	// Reset state
+	uci.thinking = false
+	uci.pondering = false
+	uci.infinite = false
+	uci.searchDone = false
+	uci.cancel = nil
+	uci.engineOutput = nil
+	uci.searchResult = SearchInfo{}
 }
 
 // searchInfoToUci converts a SearchInfo structure into a string that follows UCI's "info" line format.
@@ -298,6 +407,9 @@ func (uci *Protocol) ponderhitCommand(_ []string) error {
 func searchInfoToUci(si SearchInfo) string {
 	sb := &strings.Builder{}
 	fmt.Fprintf(sb, "info depth %v", si.Depth)
+	if si.MultiPV > 0 {
+		fmt.Fprintf(sb, " multipv %v", si.MultiPV)
+	}
 	if si.Score.Mate != 0 {
 		fmt.Fprintf(sb, " score mate %v", si.Score.Mate)
 	} else {
@@ -306,7 +418,8 @@ func searchInfoToUci(si SearchInfo) string {
 
 	timeMs := si.Time.Milliseconds()
 	nps := si.Nodes * 1000 / (timeMs + 1)
-	fmt.Fprintf(sb, " nodes %v time %v nps %v", si.Nodes, timeMs, nps)
+	fmt.Fprintf(sb, " nodes %v time %v nps %v hashfull %v tbhits %v",
+		si.Nodes, timeMs, nps, si.HashFull, si.TBHits)
 	if len(si.MainLine) != 0 {
 		fmt.Fprintf(sb, " pv")
 		for _, move := range si.MainLine {
@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Tecu23
+// Licensed under GNU GPL v3
+
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tecu23/argov2/internal/hash"
+	"github.com/Tecu23/argov2/pkg/attacks"
+	"github.com/Tecu23/argov2/pkg/constants"
+	"github.com/Tecu23/argov2/pkg/engine"
+	"github.com/Tecu23/argov2/pkg/util"
+)
+
+func init() {
+	attacks.InitPawnAttacks()
+	attacks.InitKnightAttacks()
+	attacks.InitKingAttacks()
+	attacks.InitRays()
+	attacks.InitSliderPiecesAttacks(constants.Bishop)
+	attacks.InitSliderPiecesAttacks(constants.Rook)
+
+	util.InitFen2Sq()
+	hash.Init()
+}
+
+func TestNewKeepsOptions(t *testing.T) {
+	ownBook := false
+	options := make([]Option, 1, 4)
+	options[0] = &BoolOption{Name: "OwnBook", Value: &ownBook}
+
+	uci := New("ArGO", "Tecu23", "test", nil, options)
+	if len(uci.options) != 3 {
+		t.Errorf("protocol has %d options, want 3", len(uci.options))
+	}
+	if spare := options[:2]; spare[1] != nil {
+		t.Errorf("New wrote %v into the spare capacity of the options", spare[1])
+	}
+}
+
+// runProtocol runs the protocol with a search engine on pipes. Commands are
+// sent with send, and the bestmove lines arrive on the channel returned.
+func runProtocol(t *testing.T) (send func(string), bestmoves <-chan string) {
+	t.Helper()
+	eng := engine.NewEngine(engine.NewOptions())
+	uci := New("Argo", "Tecu23", "test", eng, nil)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	uci.in, uci.out = inR, outW
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uci.Run(log.New(io.Discard, "", 0))
+	}()
+
+	lines := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "bestmove") {
+				lines <- line
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		inW.Close()
+		<-done
+		outW.Close()
+	})
+	send = func(command string) {
+		fmt.Fprintln(inW, command)
+	}
+	return send, lines
+}
+
+// TestPonderHit checks that a ponder search holds back its best move until
+// ponderhit, and then finishes on the clock of the move time.
+func TestPonderHit(t *testing.T) {
+	send, bestmoves := runProtocol(t)
+
+	send("position startpos moves e2e4")
+	send("go ponder movetime 100")
+
+	// The move time doesn't count before ponderhit
+	select {
+	case line := <-bestmoves:
+		t.Fatalf("got %q before ponderhit", line)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	send("ponderhit")
+	select {
+	case line := <-bestmoves:
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[2] != "ponder" {
+			t.Errorf("got %q, want bestmove with a move to ponder on", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no bestmove after ponderhit")
+	}
+}
+
+// TestPonderStop checks that stop ends a ponder search with its best move
+func TestPonderStop(t *testing.T) {
+	send, bestmoves := runProtocol(t)
+
+	send("go ponder wtime 1000 btime 1000")
+	time.Sleep(50 * time.Millisecond)
+	send("stop")
+
+	select {
+	case line := <-bestmoves:
+		if !strings.HasPrefix(line, "bestmove ") {
+			t.Errorf("got %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no bestmove after stop")
+	}
+}
+
+// TestInfiniteStop checks that an infinite search holds back its best move
+// until stop, even when the search ends by itself. Black has a single legal
+// move, so the search ends at once.
+func TestInfiniteStop(t *testing.T) {
+	send, bestmoves := runProtocol(t)
+
+	send("position fen 7k/8/8/8/8/8/8/K5R1 b - - 0 1")
+	send("go infinite")
+
+	select {
+	case line := <-bestmoves:
+		t.Fatalf("got %q before stop", line)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	send("stop")
+	select {
+	case line := <-bestmoves:
+		if line != "bestmove h8h7" {
+			t.Errorf("got %q, want bestmove h8h7", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no bestmove after stop")
+	}
+}